@@ -1,3 +1,6 @@
+// Keeps the Go toolchain from building this alongside main.go
+//go:build ignore
+
 #include <stdio.h>
 #include <stdlib.h>
 #include <string.h>
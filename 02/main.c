@@ -1,3 +1,6 @@
+// Keeps the Go toolchain from building this alongside main.go
+//go:build ignore
+
 // This is really bad C code only because I didn't want to deal with malloc.
 // If I did this would be significantly more OOP since I like that
 
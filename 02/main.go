@@ -7,6 +7,8 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+
+	"github.com/poweredbypie/aoc.2023/parallel"
 )
 
 func PanicIf(err error) {
@@ -15,15 +17,16 @@ func PanicIf(err error) {
 	}
 }
 
-func ForEachLine(file *os.File, f func(string)) {
+func ReadLines(file *os.File) []string {
 	_, err := file.Seek(0, 0)
 	PanicIf(err)
 
+	lines := []string{}
 	scan := bufio.NewScanner(file)
 	for scan.Scan() {
-		line := scan.Text()
-		f(line)
+		lines = append(lines, scan.Text())
 	}
+	return lines
 }
 
 // A specific number of colored cubes.
@@ -91,26 +94,23 @@ func PartA(file *os.File) int {
 		}
 	}
 
-	sum := 0
-	ForEachLine(file, func(line string) {
+	// Every game is independent, so check them all at once
+	return parallel.Sum(ReadLines(file), func(line string) int {
 		game := NewGame(line)
 		for _, set := range game.Sets {
 			for _, draw := range set.Draws {
 				if countForColor(draw) < draw.Count {
-					return
+					return 0
 				}
 			}
 		}
 
-		sum += game.Id
+		return game.Id
 	})
-
-	return sum
 }
 
 func PartB(file *os.File) int {
-	powerSum := 0
-	ForEachLine(file, func(line string) {
+	return parallel.Sum(ReadLines(file), func(line string) int {
 		game := NewGame(line)
 		maxRed := 0
 		maxGreen := 0
@@ -134,10 +134,8 @@ func PartB(file *os.File) int {
 		}
 
 		power := maxRed * maxGreen * maxBlue
-		powerSum += power
+		return power
 	})
-
-	return powerSum
 }
 
 func main() {
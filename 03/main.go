@@ -1,11 +1,12 @@
 package main
 
 import (
-	"bufio"
 	"fmt"
 	"os"
 	"strconv"
 	"unicode"
+
+	"github.com/poweredbypie/aoc.2023/grid"
 )
 
 func PanicIf(err error) {
@@ -15,83 +16,24 @@ func PanicIf(err error) {
 }
 
 type Schematic struct {
-	Lines      []string
-	Rows, Cols int
+	*grid.Grid
 }
 
 func Digit(char byte) bool {
 	return unicode.IsDigit(rune(char))
 }
 
-type Coord struct {
-	Row, Col int
-}
-
-func (c Coord) Format(f fmt.State, _ rune) {
-	f.Write([]byte(fmt.Sprintf("(%v, %v)", c.Row, c.Col)))
-}
-
-func (s *Schematic) At(coord Coord) byte {
-	return s.Lines[coord.Row][coord.Col]
-}
-
-type SchematicIter struct {
-	schem *Schematic
-	curr  Coord
-}
-
-func (s *Schematic) NewIterator() SchematicIter {
-	return SchematicIter{
-		// Initially use  -1 for column since you need to call Next() first
-		s, Coord{0, -1},
+// Check the coord itself and everything around it, including diagonals
+func (s *Schematic) CheckAround(coord grid.Coord, compare func(char byte, coord grid.Coord) bool) bool {
+	if compare(*s.At(coord), coord) {
+		return true
 	}
-}
-
-func (s *SchematicIter) Next() bool {
-	s.curr.Col += 1
-	if s.curr.Col >= s.schem.Cols {
-		s.curr.Row += 1
-		s.curr.Col = 0
-		if s.curr.Row >= s.schem.Rows {
-			return false
+	for around := range s.Neighbors8(coord) {
+		if compare(*s.At(around), around) {
+			return true
 		}
 	}
-
-	return true
-}
-
-func (s *SchematicIter) Coord() Coord {
-	return s.curr
-}
-
-func (s *Schematic) CheckAround(coord Coord, compare func(char byte, coord Coord) bool) bool {
-	compareWrap := func(rowVal, colVal int) bool {
-		coord := Coord{rowVal, colVal}
-		return compare(s.At(coord), coord)
-	}
-	check := func(rowVal int) bool {
-		if rowVal >= 0 && rowVal < s.Rows {
-			// Check specified row
-			if coord.Col > 0 {
-				// Check specified row, to the left
-				if compareWrap(rowVal, coord.Col-1) {
-					return true
-				}
-			}
-			// Check specified row, same coord.Column
-			if compareWrap(rowVal, coord.Col) {
-				return true
-			}
-			if coord.Col < s.Cols-1 {
-				// Check specified row, to the right
-				if compareWrap(rowVal, coord.Col+1) {
-					return true
-				}
-			}
-		}
-		return false
-	}
-	return check(coord.Row-1) || check(coord.Row) || check(coord.Row+1)
+	return false
 }
 
 // For part A
@@ -100,7 +42,7 @@ func (s *Schematic) FindValidNumbers() []int {
 	numStr := ""
 	nums := []int{}
 
-	isSymbol := func(char byte, _ Coord) bool {
+	isSymbol := func(char byte, _ grid.Coord) bool {
 		if Digit(char) {
 			return false
 		} else {
@@ -108,14 +50,13 @@ func (s *Schematic) FindValidNumbers() []int {
 		}
 	}
 
-	iter := s.NewIterator()
-	for iter.Next() {
-		char := s.At(iter.Coord())
+	for coord := range s.All() {
+		char := *s.At(coord)
 
 		if Digit(char) {
 			// Still in a number (or starting a new one)
 			numStr += string(char)
-			if s.CheckAround(iter.Coord(), isSymbol) {
+			if s.CheckAround(coord, isSymbol) {
 				keep = true
 			}
 		} else {
@@ -135,13 +76,12 @@ func (s *Schematic) FindValidNumbers() []int {
 }
 
 // Find all stars (for part B)
-func (s *Schematic) FindStars() []Coord {
-	coords := []Coord{}
+func (s *Schematic) FindStars() []grid.Coord {
+	coords := []grid.Coord{}
 
-	iter := s.NewIterator()
-	for iter.Next() {
-		if s.At(iter.Coord()) == '*' {
-			coords = append(coords, iter.Coord())
+	for coord := range s.All() {
+		if *s.At(coord) == '*' {
+			coords = append(coords, coord)
 		}
 	}
 
@@ -149,10 +89,10 @@ func (s *Schematic) FindStars() []Coord {
 }
 
 // Get the leftmost digit for this number
-func (s *Schematic) ParentDigit(coord Coord) Coord {
+func (s *Schematic) ParentDigit(coord grid.Coord) grid.Coord {
 	iter := coord
 	for ; iter.Col >= 0; iter.Col -= 1 {
-		if !Digit(s.At(iter)) {
+		if !Digit(*s.At(iter)) {
 			iter.Col += 1
 			return iter
 		}
@@ -162,11 +102,11 @@ func (s *Schematic) ParentDigit(coord Coord) Coord {
 	return iter
 }
 
-func (s *Schematic) ParentDigitToNum(parent Coord) int {
-	findRight := func() Coord {
+func (s *Schematic) ParentDigitToNum(parent grid.Coord) int {
+	findRight := func() grid.Coord {
 		iter := parent
 		for ; iter.Col < s.Cols; iter.Col += 1 {
-			if !Digit(s.At(iter)) {
+			if !Digit(*s.At(iter)) {
 				iter.Col -= 1
 				return iter
 			}
@@ -176,8 +116,8 @@ func (s *Schematic) ParentDigitToNum(parent Coord) int {
 		return iter
 	}
 	right := findRight()
-	slice := s.Lines[parent.Row][parent.Col : right.Col+1]
-	num, err := strconv.Atoi(slice)
+	slice := s.Row(parent.Row)[parent.Col : right.Col+1]
+	num, err := strconv.Atoi(string(slice))
 	PanicIf(err)
 	return num
 }
@@ -185,22 +125,8 @@ func (s *Schematic) ParentDigitToNum(parent Coord) int {
 func NewSchematic(file *os.File) Schematic {
 	_, err := file.Seek(0, 0)
 	PanicIf(err)
-	scan := bufio.NewScanner(file)
-	schem := Schematic{}
-	for scan.Scan() {
-		schem.Lines = append(schem.Lines, scan.Text())
-	}
-
-	schem.Rows = len(schem.Lines)
-	schem.Cols = len(schem.Lines[0])
-
-	for idx, line := range schem.Lines {
-		if len(line) != schem.Cols {
-			panic(fmt.Sprintf("Line %v has differing length from first line (%v vs. %v)", idx, schem.Cols, len(line)))
-		}
-	}
-
-	return schem
+	// Grid checks that every line is the same length
+	return Schematic{grid.ReadFile(file)}
 }
 
 func PartA(schem Schematic) int {
@@ -214,8 +140,8 @@ func PartA(schem Schematic) int {
 func PartB(schem Schematic) uint64 {
 	sum := uint64(0)
 	for _, star := range schem.FindStars() {
-		parentMap := make(map[Coord]bool)
-		schem.CheckAround(star, func(char byte, coord Coord) bool {
+		parentMap := make(map[grid.Coord]bool)
+		schem.CheckAround(star, func(char byte, coord grid.Coord) bool {
 			if Digit(char) {
 				// Digits can belong to the same number, so use this to determine it
 				parentMap[schem.ParentDigit(coord)] = true
@@ -226,7 +152,7 @@ func PartB(schem Schematic) uint64 {
 			continue
 		}
 		// Convert the map to a slice
-		parents := []Coord{}
+		parents := []grid.Coord{}
 		for parent := range parentMap {
 			parents = append(parents, parent)
 		}
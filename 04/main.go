@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"os"
 	"slices"
-	"strconv"
 	"strings"
+
+	"github.com/poweredbypie/aoc.2023/parallel"
+	"github.com/poweredbypie/aoc.2023/parse"
 )
 
 func PanicIf(err error) {
@@ -16,44 +18,38 @@ func PanicIf(err error) {
 }
 
 func main() {
-	strsToNums := func(strs []string) []int {
-		nums := []int{}
-		for _, str := range strs {
-			if str == "" {
-				continue
-			}
-			num, err := strconv.Atoi(str)
-			PanicIf(err)
-			nums = append(nums, num)
-		}
-		return nums
-	}
 	file, err := os.Open("input")
 	PanicIf(err)
 	scan := bufio.NewScanner(file)
-
-	part1Sum := 0
-	cardIdx := 1
-	copies := [300]int{}
+	lines := []string{}
 	for scan.Scan() {
-		nums := strings.Split(scan.Text(), ":")[1]
-		split := strings.Split(nums, "|")
-		given := strsToNums(strings.Split(split[0], " "))
-		mine := strsToNums(strings.Split(split[1], " "))
+		lines = append(lines, scan.Text())
+	}
 
-		part1Score := 0
-		part2Score := 0
+	// How many of our numbers match on each card; cards don't depend on each other for this
+	matches := parallel.Map(lines, func(line string) int {
+		_, nums, _ := parse.Labeled(line)
+		givenStr, mineStr, _ := strings.Cut(nums, "|")
+		given := parse.Ints(givenStr)
+		mine := parse.Ints(mineStr)
 
+		count := 0
 		for _, num := range given {
 			if slices.Contains(mine, num) {
-				part2Score += 1
-				if part1Score == 0 {
-					part1Score = 1
-				} else {
-					part1Score *= 2
-				}
+				count += 1
 			}
 		}
+		return count
+	})
+
+	part1Sum := 0
+	cardIdx := 1
+	copies := [300]int{}
+	for _, part2Score := range matches {
+		part1Score := 0
+		if part2Score > 0 {
+			part1Score = 1 << (part2Score - 1)
+		}
 
 		for idx := 0; idx < part2Score; idx += 1 {
 			copies[idx+1+cardIdx] += 1 + copies[cardIdx]
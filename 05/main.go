@@ -2,15 +2,16 @@ package main
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 	"slices"
-	"strconv"
 	"strings"
-)
 
-var debug = log.New(os.Stderr, "DEBUG: ", 0)
+	"github.com/poweredbypie/aoc.2023/logging"
+	"github.com/poweredbypie/aoc.2023/parse"
+)
 
 func PanicIf(err error) {
 	if err != nil {
@@ -68,32 +69,22 @@ func NewSeeds() Seeds {
 	}
 }
 
-func getSeedList(line string) []string {
-	seedList := []string{}
-
-	if strings.HasPrefix(line, "seeds: ") {
-		seedList = strings.Split(
-			strings.Trim(
-				strings.Split(line, ":")[1],
-				" ",
-			), " ",
-		)
+func getSeedList(line string) []int {
+	if label, seeds, ok := parse.LabeledInts(line); ok && label == "seeds" {
+		return seeds
 	}
-
-	return seedList
+	return []int{}
 }
 
 func NewList(line string) Seeds {
 	seeds := NewSeeds()
 	list := getSeedList(line)
-	for _, seed := range list {
-		id, err := strconv.Atoi(seed)
-		PanicIf(err)
+	for _, id := range list {
 		seeds.Ranges = append(seeds.Ranges, SeedRange{
 			Start:  id,
 			Length: 1,
 		})
-		debug.Printf("Adding seed ID %v", id)
+		slog.Debug("Adding seed", "id", id)
 	}
 
 	return seeds
@@ -104,12 +95,9 @@ func NewRanges(line string) Seeds {
 	list := getSeedList(line)
 	for idx := 0; idx < len(list); idx += 2 {
 		// Start and length pairs
-		start, err := strconv.Atoi(list[idx])
-		PanicIf(err)
-		length, err := strconv.Atoi(list[idx+1])
-		PanicIf(err)
+		start, length := list[idx], list[idx+1]
 		seeds.Ranges = append(seeds.Ranges, SeedRange{start, length})
-		debug.Printf("Adding seed range %v to %v", start, start+length-1)
+		slog.Debug("Adding seed range", "start", start, "end", start+length-1)
 	}
 
 	return seeds
@@ -138,7 +126,7 @@ func (s *Seeds) Next() bool {
 			return false
 		}
 		currRange = s.Ranges[s.currIdx]
-		debug.Printf("Starting new range (%v to %v)", currRange.Start, currRange.Start+currRange.Length-1)
+		slog.Debug("Starting new range", "start", currRange.Start, "end", currRange.Start+currRange.Length-1)
 		s.currSeed = currRange.Start
 	}
 
@@ -155,21 +143,15 @@ func (s *Seeds) Seed() int {
 func GetRemaps(scan *bufio.Scanner) []*Remap {
 	remaps := []*Remap{}
 
-	for scan.Scan() {
-		line := scan.Text()
-		if line == "" {
-			break
+	nums := []int{}
+	for _, line := range parse.Block(scan) {
+		nums = parse.AppendInts(nums[:0], line)
+		if len(nums) != 3 {
+			panic("Expected 3 numbers for a remap, got: " + line)
 		}
+		dest, src, length := nums[0], nums[1], nums[2]
 
-		mappings := strings.Split(line, " ")
-		dest, err := strconv.Atoi(mappings[0])
-		PanicIf(err)
-		src, err := strconv.Atoi(mappings[1])
-		PanicIf(err)
-		length, err := strconv.Atoi(mappings[2])
-		PanicIf(err)
-
-		debug.Printf("Found remap from %v to %v (length %v)", src, dest, length)
+		slog.Debug("Found remap", "src", src, "dest", dest, "length", length)
 
 		remaps = append(remaps, &Remap{
 			SrcStart: src,
@@ -197,12 +179,12 @@ func GetMaps(scan *bufio.Scanner) Maps {
 	maps := make(Maps)
 
 	for scan.Scan() {
-		line := scan.Text()
-		if strings.Contains(line, "map:") {
-			mapName := strings.Split(strings.TrimSuffix(line, " map:"), "-")
+		label, _, ok := parse.Labeled(scan.Text())
+		if name, isMap := strings.CutSuffix(label, " map"); ok && isMap {
+			mapName := strings.Split(name, "-")
 			from := mapName[0]
 			to := mapName[2]
-			debug.Printf("Found map from %v to %v", from, to)
+			slog.Debug("Found map", "from", from, "to", to)
 			maps[from] = &Map{
 				From:   from,
 				To:     to,
@@ -222,8 +204,8 @@ func (m *Maps) Follow(start string, src int) int {
 		newVal := mapping.GetMapping(val)
 		val = newVal
 	}
-	// This is too noisy for part B
-	// debug.Printf("Mapping %v (%v) to %v (%v)", src, start, val, next)
+	// This is too noisy for part B, and even a disabled record costs too much per seed
+	// slog.Debug("Mapped", "src", src, "from", start, "val", val, "to", next)
 
 	return val
 }
@@ -241,6 +223,8 @@ func (m *Maps) GetMin(seeds *Seeds) int {
 }
 
 func main() {
+	logging.Flag(flag.CommandLine)
+	flag.Parse()
 	file, err := os.Open("input")
 	PanicIf(err)
 	defer file.Close()
@@ -5,9 +5,10 @@ import (
 	"fmt"
 	"math"
 	"os"
-	"regexp"
 	"strconv"
 	"strings"
+
+	"github.com/poweredbypie/aoc.2023/parse"
 )
 
 func PanicIf(err error) {
@@ -26,27 +27,17 @@ func NewRaces(file *os.File) []Race {
 	_, err := file.Seek(0, 0)
 	PanicIf(err)
 
-	vals := func(line string) []int {
-		regex := regexp.MustCompile("[0-9]+")
-		nums := []int{}
-		for _, str := range regex.FindAllString(line, -1) {
-			num, err := strconv.Atoi(str)
-			PanicIf(err)
-			nums = append(nums, num)
-		}
-		return nums
-	}
-
 	races := []Race{}
 	scan := bufio.NewScanner(file)
 	for scan.Scan() {
 		line := scan.Text()
-		if strings.HasPrefix(line, "Time: ") {
-			for _, num := range vals(line) {
+		label, nums, _ := parse.LabeledInts(line)
+		if label == "Time" {
+			for _, num := range nums {
 				races = append(races, Race{Duration: num})
 			}
-		} else if strings.HasPrefix(line, "Distance: ") {
-			for idx, num := range vals(line) {
+		} else if label == "Distance" {
+			for idx, num := range nums {
 				races[idx].Record = num
 			}
 		} else {
@@ -66,13 +57,13 @@ func NewRace(file *os.File) Race {
 	scan := bufio.NewScanner(file)
 	for scan.Scan() {
 		line := scan.Text()
+		label, rest, _ := parse.Labeled(line)
 		// Remove _ALL_ spaces
-		line = strings.ReplaceAll(line, " ", "")
-		val, err := strconv.Atoi(strings.Split(line, ":")[1])
+		val, err := strconv.Atoi(strings.ReplaceAll(rest, " ", ""))
 		PanicIf(err)
-		if strings.HasPrefix(line, "Time:") {
+		if label == "Time" {
 			race.Duration = val
-		} else if strings.HasPrefix(line, "Distance:") {
+		} else if label == "Distance" {
 			race.Record = val
 		} else {
 			panic("Line has unexpected content: " + line)
@@ -1,12 +1,16 @@
 package main
 
 import (
-	"bufio"
+	"context"
+	"flag"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 	"slices"
 	"strings"
+
+	"github.com/poweredbypie/aoc.2023/grid"
+	"github.com/poweredbypie/aoc.2023/logging"
 )
 
 func PanicIf(err error) {
@@ -15,130 +19,92 @@ func PanicIf(err error) {
 	}
 }
 
-type Coord struct {
-	Row int
-	Col int
-}
-
-func (c Coord) Format(f fmt.State, verb rune) {
-	f.Write([]byte(fmt.Sprintf("(%v, %v)", c.Row, c.Col)))
-}
-
-func (c Coord) Equal(coord Coord) bool {
-	return c.Row == coord.Row && c.Col == coord.Col
-}
-
-type Dir int
-
-const (
-	Up Dir = iota
-	Left
-	Right
-	Down
-)
-
-func (d Dir) Format(f fmt.State, verb rune) {
-	text := "Unknown"
-	switch d {
-	case Up:
-		text = "Up"
-	case Left:
-		text = "Left"
-	case Right:
-		text = "Right"
-	case Down:
-		text = "Down"
-	}
-	f.Write([]byte(text))
-}
-
-func (d Dir) Flip() Dir {
-	switch d {
-	case Up:
-		return Down
-	case Left:
-		return Right
-	case Right:
-		return Left
-	case Down:
-		return Up
-	default:
-		panic("Unexpected direction")
-	}
-}
-
-func DirsFor(char byte) (Dir, Dir) {
+func TryDirsFor(char byte) (grid.Dir, grid.Dir, bool) {
 	switch char {
 	case '|':
-		return Up, Down
+		return grid.Up, grid.Down, true
 	case '-':
-		return Left, Right
+		return grid.Left, grid.Right, true
 	case 'L':
-		return Up, Right
+		return grid.Up, grid.Right, true
 	case 'J':
-		return Up, Left
+		return grid.Up, grid.Left, true
 	case '7':
-		return Left, Down
+		return grid.Left, grid.Down, true
 	case 'F':
-		return Right, Down
+		return grid.Right, grid.Down, true
 	default:
+		return grid.Up, grid.Up, false
+	}
+}
+
+func DirsFor(char byte) (grid.Dir, grid.Dir) {
+	in, out, ok := TryDirsFor(char)
+	if !ok {
 		panic("Unexpected char " + string(char))
 	}
+	return in, out
+}
+
+// Opposite of DirsFor; first has to come before second in grid.Dirs order
+func CharFor(first, second grid.Dir) byte {
+	switch {
+	case first == grid.Up && second == grid.Left:
+		return 'J'
+	case first == grid.Up && second == grid.Right:
+		return 'L'
+	case first == grid.Up && second == grid.Down:
+		return '|'
+	case first == grid.Left && second == grid.Right:
+		return '-'
+	case first == grid.Left && second == grid.Down:
+		return '7'
+	case first == grid.Right && second == grid.Down:
+		return 'F'
+	default:
+		panic("Couldn't match dirs")
+	}
 }
 
 type Map struct {
-	lines  [][]byte
-	rows   int
-	cols   int
-	border map[Coord]bool
+	*grid.Grid
+	border map[grid.Coord]bool
+	// Distance from the start for every tile in border
+	dists map[grid.Coord]int
 }
 
 func MapFromFile(file *os.File) Map {
-	lines := [][]byte{}
-	scan := bufio.NewScanner(file)
-	for scan.Scan() {
-		lines = append(lines, []byte(scan.Text()))
-	}
-	return NewMap(lines)
+	return NewMap(grid.ReadFile(file))
 }
 
-func NewMap(bytes [][]byte) Map {
+func NewMap(g *grid.Grid) Map {
 	return Map{
-		lines:  bytes,
-		rows:   len(bytes),
-		cols:   len(bytes[0]),
-		border: make(map[Coord]bool),
+		Grid:   g,
+		border: make(map[grid.Coord]bool),
+		dists:  make(map[grid.Coord]int),
 	}
 }
 
-func (m *Map) At(coord Coord) *byte {
-	return &m.lines[coord.Row][coord.Col]
+// Whether debug records get logged. Check this first for records made once per tile
+func debugging() bool {
+	return slog.Default().Enabled(context.Background(), slog.LevelDebug)
 }
 
 // Stuff for part A (follow the track around)
-func (m *Map) Move(coord Coord, out Dir) (Coord, Dir) {
-	// Assumption: loop does not lead into border
-	switch out {
-	case Up:
-		return Coord{coord.Row - 1, coord.Col}, out
-	case Left:
-		return Coord{coord.Row, coord.Col - 1}, out
-	case Right:
-		return Coord{coord.Row, coord.Col + 1}, out
-	case Down:
-		return Coord{coord.Row + 1, coord.Col}, out
-	default:
-		panic("Unexpected direction")
-	}
+func (m *Map) Move(coord grid.Coord, out grid.Dir) (grid.Coord, grid.Dir) {
+	return coord.Move(out), out
 }
 
-func (m *Map) MoveDebug(coord Coord, out Dir) (Coord, Dir) {
+func (m *Map) MoveDebug(coord grid.Coord, out grid.Dir) (grid.Coord, grid.Dir) {
 	newVal, out := m.Move(coord, out)
-	log.Printf("%v {%c} -(%v)> %v {%c}", coord, *m.At(coord), out, newVal, *m.At(newVal))
+	// This runs on every step, so don't even build the record unless it's going somewhere
+	if debugging() {
+		slog.Debug("Move", "from", coord, "fromChar", string(*m.At(coord)), "dir", out, "to", newVal, "toChar", string(*m.At(newVal)))
+	}
 	return newVal, out
 }
 
-func (m *Map) Follow(coord Coord, from Dir) (Coord, Dir) {
+func (m *Map) Follow(coord grid.Coord, from grid.Dir) (grid.Coord, grid.Dir) {
 	val := *m.At(coord)
 	from = from.Flip()
 	in, out := DirsFor(val)
@@ -149,79 +115,139 @@ func (m *Map) Follow(coord Coord, from Dir) (Coord, Dir) {
 	}
 }
 
-func (m *Map) Loop(start Coord, dir Dir) int {
+func (m *Map) Loop(start grid.Coord, dir grid.Dir) int {
 	for curr, dir, dist := start, dir.Flip(), 1; ; dist += 1 {
 		m.border[curr] = true
+		// Steps taken going one way around, fixed up below once we know the full length
+		m.dists[curr] = dist - 1
 		curr, dir = m.Follow(curr, dir)
 		// This needs to happen after the first check
 		if curr.Equal(start) {
-			log.Printf("Stopped at distance %v", dist)
+			slog.Info("Stopped", "dist", dist)
+			// The other way around might be shorter
+			for coord, steps := range m.dists {
+				m.dists[coord] = min(steps, dist-steps)
+			}
 			return dist / 2
 		}
 	}
 }
 
-func (m *Map) ConnectTrack(start Coord) byte {
-	all := []Dir{Up, Left, Right, Down}
-	dirs := []Dir{}
-	for _, dir := range all {
+// Distance from the start for every tile on the loop. Loop() needs to be called first.
+func (m *Map) Distances() map[grid.Coord]int {
+	return m.dists
+}
+
+// All tiles that are the farthest away from the start (one, or two for odd length loops)
+func (m *Map) Farthest() []grid.Coord {
+	far := 0
+	coords := []grid.Coord{}
+	for coord, dist := range m.dists {
+		if dist > far {
+			far = dist
+			coords = coords[:0]
+		}
+		if dist == far {
+			coords = append(coords, coord)
+		}
+	}
+	slices.SortFunc(coords, func(one, two grid.Coord) int {
+		if one.Row != two.Row {
+			return one.Row - two.Row
+		}
+		return one.Col - two.Col
+	})
+	return coords
+}
+
+// Follow the track from start heading out in dir until we either make it back to start or run off the track.
+// Returns the tiles visited along the way and whether they form a closed loop.
+func (m *Map) Trace(start grid.Coord, dir grid.Dir) ([]grid.Coord, bool) {
+	path := []grid.Coord{start}
+	for curr := start; ; {
+		next, _ := m.Move(curr, dir)
+		if !m.InBounds(next) {
+			return path, false
+		}
+		// The next tile has to connect back to where we came from
+		from := dir.Flip()
+		in, out, ok := TryDirsFor(*m.At(next))
+		if !ok || (in != from && out != from) {
+			return path, false
+		}
+		if next.Equal(start) {
+			return path, true
+		}
+		path = append(path, next)
+		if in == from {
+			dir = out
+		} else {
+			dir = in
+		}
+		curr = next
+	}
+}
+
+func (m *Map) ConnectTrack(start grid.Coord) byte {
+	dirs := []grid.Dir{}
+	for _, dir := range []grid.Dir{grid.Up, grid.Left, grid.Right, grid.Down} {
 		around, _ := m.Move(start, dir)
-		val := *m.At(around)
-		in, out := DirsFor(val)
+		if !m.InBounds(around) {
+			continue
+		}
+		in, out, ok := TryDirsFor(*m.At(around))
 		flip := dir.Flip()
-		if in == flip || out == flip {
+		if ok && (in == flip || out == flip) {
 			dirs = append(dirs, dir)
 		}
 	}
-	if len(dirs) != 2 {
-		panic("Too many or too little directions for ConnectTrack")
-	}
-	// "Sort" so the order is consistent
-	slices.Sort(dirs)
-	first, second := dirs[0], dirs[1]
-	var char byte
-	switch {
-	case first == Up && second == Left:
-		char = 'J'
-	case first == Up && second == Right:
-		char = 'L'
-	case first == Up && second == Down:
-		char = '|'
-	case first == Left && second == Right:
-		char = '-'
-	case first == Left && second == Down:
-		char = '7'
-	case first == Right && second == Down:
-		char = 'F'
-	default:
-		panic("Couldn't match dirs")
+	// If more than two neighbors connect, only the pair that actually closes a loop is right.
+	// dirs is already in order, so pairs can be passed straight to CharFor
+	for i := range dirs {
+		for j := i + 1; j < len(dirs); j += 1 {
+			char := CharFor(dirs[i], dirs[j])
+			*m.At(start) = char
+			if _, closed := m.Trace(start, dirs[i]); closed {
+				return char
+			}
+		}
 	}
-	*m.At(start) = char
-	return char
+	*m.At(start) = 'S'
+	panic("Couldn't find a loop going through the start")
 }
 
-func (m *Map) Start() Coord {
-	c := Coord{}
-	for c.Row = 0; c.Row < m.rows; c.Row += 1 {
-		for c.Col = 0; c.Col < m.cols; c.Col += 1 {
-			if *m.At(c) == 'S' {
-				return c
-			}
+// Find every closed loop on the map, not just the one going through the start.
+// Call ConnectTrack() first, otherwise the start tile won't be part of any loop.
+func (m *Map) AllLoops() [][]grid.Coord {
+	loops := [][]grid.Coord{}
+	seen := make(map[grid.Coord]bool)
+	for c := range m.All() {
+		dir, _, ok := TryDirsFor(*m.At(c))
+		if !ok || seen[c] {
+			continue
+		}
+		// Pipes only connect in pairs, so if tracing fails none of the path can be on a loop either
+		path, closed := m.Trace(c, dir)
+		for _, coord := range path {
+			seen[coord] = true
+		}
+		if closed {
+			loops = append(loops, path)
 		}
 	}
-	panic("Couldn't find start of loop")
+	return loops
 }
 
-// Stuff for part B (fill the outside and find leftover inside
-func (m *Map) Dump(filename string) {
-	file, err := os.Create(filename)
-	PanicIf(err)
-	for _, line := range m.lines {
-		file.Write(line)
-		file.Write([]byte("\n"))
+func (m *Map) Start() grid.Coord {
+	for c := range m.All() {
+		if *m.At(c) == 'S' {
+			return c
+		}
 	}
+	panic("Couldn't find start of loop")
 }
 
+// Stuff for part B (fill the outside and find leftover inside
 const UpDownLarge = `
 .|.
 .|.
@@ -277,14 +303,16 @@ func GetLarge(char byte) [][]byte {
 			bytes = append(bytes, []byte(str))
 		}
 	}
-	log.Printf("Got %v byte arrays in %v", len(bytes), bytes)
+	if debugging() {
+		slog.Debug("Got large tile", "char", string(char), "rows", len(bytes))
+	}
 	return bytes
 }
 
-func (m *Map) WriteAt(coord Coord, bytes [][]byte) {
+func (m *Map) WriteAt(coord grid.Coord, bytes [][]byte) {
 	for row := 0; row < len(bytes); row += 1 {
 		for col := 0; col < len(bytes[row]); col += 1 {
-			curr := Coord{row + coord.Row, col + coord.Col}
+			curr := grid.Coord{Row: row + coord.Row, Col: col + coord.Col}
 			*m.At(curr) = bytes[row][col]
 		}
 	}
@@ -293,17 +321,11 @@ func (m *Map) WriteAt(coord Coord, bytes [][]byte) {
 // Expand each square into 9 squares
 // This allows a fill operation to pass through gaps that wouldn't be possible otherwise
 func (m *Map) MakeLarge() Map {
-	lines := [][]byte{}
-	for row := 0; row < m.rows*3; row += 1 {
-		line := make([]byte, m.cols*3)
-		for col := range line {
-			line[col] = '.'
-		}
-		lines = append(lines, line)
-	}
-	large := NewMap(lines)
+	large := NewMap(grid.Filled(m.Rows*3, m.Cols*3, '.'))
 	for coord := range m.border {
-		log.Printf("Processing coord %v", coord)
+		if debugging() {
+			slog.Debug("Processing", "coord", coord)
+		}
 		bytes := GetLarge(*m.At(coord))
 		// Get new coord by "large"
 		coord.Row *= 3
@@ -315,18 +337,10 @@ func (m *Map) MakeLarge() Map {
 
 // Convert a 9 square big map back into a small map
 func (m *Map) MakeSmall() Map {
-	lines := [][]byte{}
-	for row := 0; row < m.rows/3; row += 1 {
-		line := make([]byte, m.cols/3)
-		for col := range line {
-			line[col] = '.'
-		}
-		lines = append(lines, line)
-	}
-	small := NewMap(lines)
-	c := Coord{}
-	for c.Row = 0; c.Row < m.rows; c.Row += 3 {
-		for c.Col = 0; c.Col < m.cols; c.Col += 3 {
+	small := NewMap(grid.Filled(m.Rows/3, m.Cols/3, '.'))
+	c := grid.Coord{}
+	for c.Row = 0; c.Row < m.Rows; c.Row += 3 {
+		for c.Col = 0; c.Col < m.Cols; c.Col += 3 {
 			middle := c
 			middle.Row += 1
 			middle.Col += 1
@@ -340,60 +354,34 @@ func (m *Map) MakeSmall() Map {
 	return small
 }
 
-func (m *Map) FindAround(coord Coord, char byte) bool {
+func (m *Map) FindAround(coord grid.Coord, char byte) bool {
 	// Only checking as such:
 	// .*.
 	// *S*
 	// .*.
 	// where * is checked.
 	// The small board needs to check diagonals, but the big board does not because it's expanded.
-	// Above
-	if coord.Row > 0 {
-		curr := coord
-		curr.Row -= 1
-		if *m.At(curr) == char {
-			return true
-		}
-	}
-	// Left
-	if coord.Col > 0 {
-		curr := coord
-		curr.Col -= 1
-		if *m.At(curr) == char {
-			return true
-		}
-	}
-	// Right
-	if coord.Col < m.cols-1 {
-		curr := coord
-		curr.Col += 1
-		if *m.At(curr) == char {
-			return true
-		}
-	}
-	// Below
-	if coord.Row < m.rows-1 {
-		curr := coord
-		curr.Row += 1
-		if *m.At(curr) == char {
+	for around := range m.Neighbors4(coord) {
+		if *m.At(around) == char {
 			return true
 		}
 	}
 	return false
 }
 
+func IsPipe(c byte) bool {
+	return c == '|' || c == '-' || c == 'L' || c == 'J' || c == '7' || c == 'F'
+}
+
 func (m *Map) FillOutside() {
-	isBorder := func(c byte) bool {
-		return c == '|' || c == '-' || c == 'L' || c == 'J' || c == '7' || c == 'F'
-	}
-	loop := func(c Coord, nextRow func(int) int, nextCol func(int) int) int {
+	loop := func(c grid.Coord, nextRow func(int) int, nextCol func(int) int) int {
 		sum := 0
 		// Assumption: start coord is outside
 		*m.At(c) = 'O'
 		rowStart, colStart := c.Row, c.Col
-		for c.Row = rowStart; c.Row >= 0 && c.Row < m.rows; c.Row = nextRow(c.Row) {
-			for c.Col = colStart; c.Col >= 0 && c.Col < m.cols; c.Col = nextCol(c.Col) {
-				if *m.At(c) != 'O' && !isBorder(*m.At(c)) && m.FindAround(c, 'O') {
+		for c.Row = rowStart; c.Row >= 0 && c.Row < m.Rows; c.Row = nextRow(c.Row) {
+			for c.Col = colStart; c.Col >= 0 && c.Col < m.Cols; c.Col = nextCol(c.Col) {
+				if *m.At(c) != 'O' && !IsPipe(*m.At(c)) && m.FindAround(c, 'O') {
 					*m.At(c) = 'O'
 					sum += 1
 				}
@@ -408,33 +396,82 @@ func (m *Map) FillOutside() {
 	for {
 		sum := 0
 		// First: L -> R, U -> D
-		sum += loop(Coord{0, 0}, inc, inc)
+		sum += loop(grid.Coord{Row: 0, Col: 0}, inc, inc)
 		// Second: R -> L, U -> D
-		sum += loop(Coord{0, m.cols - 1}, inc, dec)
+		sum += loop(grid.Coord{Row: 0, Col: m.Cols - 1}, inc, dec)
 		// Third: L -> R, D -> U
-		sum += loop(Coord{m.rows - 1, 0}, dec, inc)
+		sum += loop(grid.Coord{Row: m.Rows - 1, Col: 0}, dec, inc)
 		// Fourth: R -> L, D -> U
-		sum += loop(Coord{m.rows - 1, m.cols - 1}, dec, dec)
+		sum += loop(grid.Coord{Row: m.Rows - 1, Col: m.Cols - 1}, dec, dec)
 		if sum == 0 {
 			break
 		}
 	}
 }
 
-func (m *Map) Count(char byte) int {
-	c := Coord{}
-	count := 0
-	for c.Row = 0; c.Row < m.rows; c.Row += 1 {
-		for c.Col = 0; c.Col < m.cols; c.Col += 1 {
-			if *m.At(c) == char {
-				count += 1
+// Stuff for part B without the large map (scanline parity)
+// Walk each row and flip between outside and inside whenever we cross the loop.
+// '|' is always a crossing. For horizontal runs, L...7 and F...J cross the row,
+// but L...J and F...7 just touch it and turn back.
+func (m *Map) walkInside(visit func(grid.Coord)) {
+	c := grid.Coord{}
+	for c.Row = 0; c.Row < m.Rows; c.Row += 1 {
+		in := false
+		// Corner that opened the current horizontal run, if any
+		var opened byte
+		for c.Col = 0; c.Col < m.Cols; c.Col += 1 {
+			if !m.border[c] {
+				if in {
+					visit(c)
+				}
+				continue
+			}
+			switch char := *m.At(c); char {
+			case '|':
+				in = !in
+			case 'L', 'F':
+				opened = char
+			case '7':
+				if opened == 'L' {
+					in = !in
+				}
+			case 'J':
+				if opened == 'F' {
+					in = !in
+				}
 			}
 		}
 	}
+}
+
+// Every inside tile, for drawing. Use CountInside() if you just need how many there are
+func (m *Map) Inside() map[grid.Coord]bool {
+	inside := make(map[grid.Coord]bool)
+	m.walkInside(func(c grid.Coord) {
+		inside[c] = true
+	})
+	return inside
+}
+
+func (m *Map) CountInside() int {
+	count := 0
+	m.walkInside(func(grid.Coord) {
+		count += 1
+	})
 	return count
 }
 
 func main() {
+	scan := flag.Bool("scan", false, "count enclosed tiles by scanline parity instead of filling a large map")
+	render := flag.String("render", "", "draw the loop to this path (.svg or .png)")
+	cell := flag.Int("cell", 8, "size of each tile in pixels when rendering")
+	pretty := flag.String("pretty", "", "print the \"small\" or \"large\" map with box-drawing characters")
+	color := flag.Bool("color", false, "color the loop, inside and outside tiles when pretty printing")
+	heatmap := flag.Bool("heatmap", false, "print the distance from the start for every tile on the loop")
+	loops := flag.Bool("loops", false, "list every closed loop on the map, not just the one through the start")
+	dump := flag.Bool("dump", false, "write the large and small maps as text to \"big\" and \"small\"")
+	logging.Flag(flag.CommandLine)
+	flag.Parse()
 	file, err := os.Open("input")
 	defer file.Close()
 	PanicIf(err)
@@ -443,12 +480,35 @@ func main() {
 	// Connect start with actual nodes it connects to
 	startChar := m.ConnectTrack(start)
 	dir, _ := DirsFor(startChar)
+	if *loops {
+		for idx, loop := range m.AllLoops() {
+			fmt.Printf("Loop %v starts at %v and has length %v\n", idx, loop[0], len(loop))
+		}
+	}
 	fmt.Printf("Max value for distance is %v\n", m.Loop(start, dir))
+	fmt.Printf("Farthest tiles from the start are %v\n", m.Farthest())
+	if *heatmap {
+		PanicIf(m.Heatmap(os.Stdout, *color))
+	}
+	if *render != "" {
+		PanicIf(m.RenderFile(*render, start, *cell))
+	}
+	if *pretty == "small" {
+		PanicIf(m.Pretty(os.Stdout, *color))
+	}
+	if *scan {
+		fmt.Printf("Number of unfilled values is %v\n", m.CountInside())
+		return
+	}
 	large := m.MakeLarge()
 	large.FillOutside()
-	// For visualization
-	large.Dump("big")
+	if *pretty == "large" {
+		PanicIf(large.Pretty(os.Stdout, *color))
+	}
 	small := large.MakeSmall()
-	small.Dump("small")
+	if *dump {
+		PanicIf(large.Dump("big"))
+		PanicIf(small.Dump("small"))
+	}
 	fmt.Printf("Number of unfilled values is %v\n", small.Count('.'))
 }
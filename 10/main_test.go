@@ -0,0 +1,137 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/poweredbypie/aoc.2023/grid"
+)
+
+func mapFrom(lines ...string) *Map {
+	m := NewMap(grid.FromStrings(lines))
+	return &m
+}
+
+func TestConnectTrackOnEdge(t *testing.T) {
+	m := mapFrom(
+		"S-7",
+		"|.|",
+		"L-J",
+	)
+	start := m.Start()
+	if char := m.ConnectTrack(start); char != 'F' {
+		t.Fatalf("Got %c for the start, expected F", char)
+	}
+	dir, _ := DirsFor('F')
+	if far := m.Loop(start, dir); far != 4 {
+		t.Errorf("Got %v for the farthest distance, expected 4", far)
+	}
+}
+
+func TestConnectTrackAmbiguous(t *testing.T) {
+	// Up, left and down all connect to S, but only up and down close a loop
+	m := mapFrom(
+		".....",
+		".F-7.",
+		"-S.|.",
+		".L-J.",
+		".....",
+	)
+	if char := m.ConnectTrack(m.Start()); char != '|' {
+		t.Errorf("Got %c for the start, expected |", char)
+	}
+}
+
+func TestConnectTrackNoLoop(t *testing.T) {
+	m := mapFrom(
+		".....",
+		".S-..",
+		".|...",
+		".....",
+	)
+	start := m.Start()
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a panic for a start that isn't on a loop")
+		}
+		if char := *m.At(start); char != 'S' {
+			t.Errorf("Got %c at the start after the panic, expected S", char)
+		}
+	}()
+	m.ConnectTrack(start)
+}
+
+func TestAllLoops(t *testing.T) {
+	m := mapFrom(
+		"S-7.F7",
+		"|.|.LJ",
+		"L-J...",
+	)
+	m.ConnectTrack(m.Start())
+	loops := m.AllLoops()
+	if len(loops) != 2 {
+		t.Fatalf("Got %v loops, expected 2", len(loops))
+	}
+	if len(loops[0]) != 8 || len(loops[1]) != 4 {
+		t.Errorf("Got loops of length %v and %v, expected 8 and 4", len(loops[0]), len(loops[1]))
+	}
+}
+
+// Part B examples from the puzzle description
+var insideExamples = []struct {
+	lines  []string
+	inside int
+}{
+	{[]string{
+		"...........",
+		".S-------7.",
+		".|F-----7|.",
+		".||.....||.",
+		".||.....||.",
+		".|L-7.F-J|.",
+		".|..|.|..|.",
+		".L--J.L--J.",
+		"...........",
+	}, 4},
+	{[]string{
+		".F----7F7F7F7F-7....",
+		".|F--7||||||||FJ....",
+		".||.FJ||||||||L7....",
+		"FJL7L7LJLJ||LJ.L-7..",
+		"L--J.L7...LJS7F-7L7.",
+		"....F-J..F7FJ|L7L7L7",
+		"....L7.F7||L7|.L7L7|",
+		".....|FJLJ|FJ|F7|.LJ",
+		"....FJL-7.||.||||...",
+		"....L---J.LJ.LJLJ...",
+	}, 8},
+	{[]string{
+		"FF7FSF7F7F7F7F7F---7",
+		"L|LJ||||||||||||F--J",
+		"FL-7LJLJ||||||LJL-77",
+		"F--JF--7||LJLJ7F7FJ-",
+		"L---JF-JLJ.||-FJLJJ7",
+		"|F|F-JF---7F7-L7L|7|",
+		"|FFJF7L7F-JF7|JL---7",
+		"7-L-JL7||F7|L7F-7F7|",
+		"L.L7LFJ|||||FJL7||LJ",
+		"L7JLJL-JLJLJL--JLJ.L",
+	}, 10},
+}
+
+func TestCountInside(t *testing.T) {
+	for idx, example := range insideExamples {
+		m := mapFrom(example.lines...)
+		start := m.Start()
+		dir, _ := DirsFor(m.ConnectTrack(start))
+		m.Loop(start, dir)
+		if count := m.CountInside(); count != example.inside {
+			t.Errorf("Example %v: scanline counted %v inside, expected %v", idx, count, example.inside)
+		}
+		large := m.MakeLarge()
+		large.FillOutside()
+		small := large.MakeSmall()
+		if count := small.Count('.'); count != example.inside {
+			t.Errorf("Example %v: filling the large map left %v inside, expected %v", idx, count, example.inside)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"github.com/poweredbypie/aoc.2023/grid"
+)
+
+// Stuff for printing maps in a terminal
+const (
+	ansiReset   = "\x1b[0m"
+	ansiLoop    = "\x1b[1;31m"
+	ansiInside  = "\x1b[32m"
+	ansiOutside = "\x1b[2m"
+)
+
+func BoxFor(char byte) rune {
+	switch char {
+	case '|':
+		return '│'
+	case '-':
+		return '─'
+	case 'L':
+		return '└'
+	case 'J':
+		return '┘'
+	case '7':
+		return '┐'
+	case 'F':
+		return '┌'
+	case '.':
+		return '·'
+	default:
+		return rune(char)
+	}
+}
+
+// Write the map using box-drawing characters, optionally colored with ANSI codes.
+// If Loop() was called, its border and Inside() decide what each tile is.
+// Otherwise (e.g. the large map) every pipe is part of the loop and 'O' is outside, as left by FillOutside().
+func (m *Map) Pretty(w io.Writer, colored bool) error {
+	known := len(m.border) > 0
+	var inside map[grid.Coord]bool
+	if known {
+		inside = m.Inside()
+	}
+	colorFor := func(c grid.Coord) string {
+		char := *m.At(c)
+		switch {
+		case known && m.border[c], !known && IsPipe(char):
+			return ansiLoop
+		case known && inside[c], !known && char != 'O':
+			return ansiInside
+		default:
+			return ansiOutside
+		}
+	}
+	out := bufio.NewWriter(w)
+	c := grid.Coord{}
+	for c.Row = 0; c.Row < m.Rows; c.Row += 1 {
+		last := ""
+		for c.Col = 0; c.Col < m.Cols; c.Col += 1 {
+			// Only emit a code when the color changes so lines stay short
+			if colored {
+				if color := colorFor(c); color != last {
+					out.WriteString(ansiReset + color)
+					last = color
+				}
+			}
+			out.WriteRune(BoxFor(*m.At(c)))
+		}
+		if colored {
+			out.WriteString(ansiReset)
+		}
+		out.WriteByte('\n')
+	}
+	return out.Flush()
+}
+
+// Write the distance from the start for each loop tile.
+// Uncolored, each tile gets a digit from 0 (at the start) to 9 (farthest away), scaled down for long loops.
+// Colored, tiles keep their box-drawing glyph and go from blue to red instead.
+func (m *Map) Heatmap(w io.Writer, colored bool) error {
+	// Short loops just get their actual distances
+	far := 9
+	for _, dist := range m.dists {
+		far = max(far, dist)
+	}
+	out := bufio.NewWriter(w)
+	c := grid.Coord{}
+	for c.Row = 0; c.Row < m.Rows; c.Row += 1 {
+		for c.Col = 0; c.Col < m.Cols; c.Col += 1 {
+			dist, ok := m.dists[c]
+			switch {
+			case !ok:
+				out.WriteByte(' ')
+			case colored:
+				// Walk the 256 color cube from pure blue (21) to pure red (196), each step is one more red and one less blue
+				step := dist * 5 / far
+				fmt.Fprintf(out, "\x1b[38;5;%vm%c", 21+step*35, BoxFor(*m.At(c)))
+			default:
+				out.WriteByte(byte('0' + dist*9/far))
+			}
+		}
+		if colored {
+			out.WriteString(ansiReset)
+		}
+		out.WriteByte('\n')
+	}
+	return out.Flush()
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/poweredbypie/aoc.2023/grid"
+)
+
+// Stuff for drawing the loop as an image
+var (
+	outsideColor = color.RGBA{0xd0, 0xd0, 0xd0, 0xff}
+	insideColor  = color.RGBA{0x7c, 0xc8, 0x6f, 0xff}
+	loopColor    = color.RGBA{0xc0, 0x20, 0x20, 0xff}
+	startColor   = color.RGBA{0x20, 0x40, 0xc0, 0xff}
+)
+
+func hex(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// Anything we can draw tiles onto
+type canvas interface {
+	Tile(coord grid.Coord, fill color.RGBA)
+	Pipe(coord grid.Coord, dir grid.Dir)
+	Start(coord grid.Coord)
+}
+
+// Draw every tile of the map onto the canvas.
+// Loop() needs to be called first so we know which tiles are part of the loop.
+func (m *Map) draw(c canvas, start grid.Coord) {
+	inside := m.Inside()
+	for coord := range m.All() {
+		switch {
+		case m.border[coord]:
+			continue
+		case inside[coord]:
+			c.Tile(coord, insideColor)
+		default:
+			c.Tile(coord, outsideColor)
+		}
+	}
+	for coord := range m.border {
+		in, out := DirsFor(*m.At(coord))
+		c.Pipe(coord, in)
+		c.Pipe(coord, out)
+	}
+	// Last so the label ends up on top of the pipes
+	c.Start(start)
+}
+
+type svgCanvas struct {
+	out  *bufio.Writer
+	cell int
+}
+
+func (s *svgCanvas) Tile(coord grid.Coord, fill color.RGBA) {
+	fmt.Fprintf(s.out, "<rect x=\"%v\" y=\"%v\" width=\"%v\" height=\"%v\" fill=\"%v\"/>\n",
+		coord.Col*s.cell, coord.Row*s.cell, s.cell, s.cell, hex(fill))
+}
+
+func (s *svgCanvas) Pipe(coord grid.Coord, dir grid.Dir) {
+	half := float64(s.cell) / 2
+	x1 := float64(coord.Col*s.cell) + half
+	y1 := float64(coord.Row*s.cell) + half
+	x2, y2 := x1, y1
+	switch dir {
+	case grid.Up:
+		y2 -= half
+	case grid.Left:
+		x2 -= half
+	case grid.Right:
+		x2 += half
+	case grid.Down:
+		y2 += half
+	}
+	fmt.Fprintf(s.out, "<line x1=\"%v\" y1=\"%v\" x2=\"%v\" y2=\"%v\"/>\n", x1, y1, x2, y2)
+}
+
+func (s *svgCanvas) Start(coord grid.Coord) {
+	fmt.Fprintf(s.out, "<rect x=\"%v\" y=\"%v\" width=\"%v\" height=\"%v\" fill=\"%v\"/>\n",
+		coord.Col*s.cell, coord.Row*s.cell, s.cell, s.cell, hex(startColor))
+	fmt.Fprintf(s.out, "<text x=\"%v\" y=\"%v\" font-size=\"%v\" font-family=\"monospace\" text-anchor=\"middle\" fill=\"white\">S</text>\n",
+		float64(coord.Col*s.cell)+float64(s.cell)/2, float64(coord.Row*s.cell)+float64(s.cell)*0.85, s.cell)
+}
+
+func (m *Map) RenderSVG(w io.Writer, start grid.Coord, cell int) error {
+	s := &svgCanvas{bufio.NewWriter(w), cell}
+	width, height := m.Cols*cell, m.Rows*cell
+	fmt.Fprintf(s.out, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%v\" height=\"%v\" viewBox=\"0 0 %v %v\">\n",
+		width, height, width, height)
+	fmt.Fprintf(s.out, "<rect width=\"%v\" height=\"%v\" fill=\"white\"/>\n", width, height)
+	// Loop segments all share a style, so set it once instead of on every line
+	fmt.Fprintf(s.out, "<style>line { stroke: %v; stroke-width: %v; stroke-linecap: square; }</style>\n",
+		hex(loopColor), max(1, cell/4))
+	m.draw(s, start)
+	fmt.Fprintln(s.out, "</svg>")
+	return s.out.Flush()
+}
+
+type pngCanvas struct {
+	img  *image.RGBA
+	cell int
+}
+
+func (p *pngCanvas) fill(rect image.Rectangle, fill color.RGBA) {
+	draw.Draw(p.img, rect, image.NewUniform(fill), image.Point{}, draw.Src)
+}
+
+func (p *pngCanvas) Tile(coord grid.Coord, fill color.RGBA) {
+	min := image.Pt(coord.Col*p.cell, coord.Row*p.cell)
+	p.fill(image.Rectangle{min, min.Add(image.Pt(p.cell, p.cell))}, fill)
+}
+
+func (p *pngCanvas) Pipe(coord grid.Coord, dir grid.Dir) {
+	// Thickness of the pipe, centered in the tile
+	thick := max(1, p.cell/4)
+	lo := (p.cell - thick) / 2
+	hi := lo + thick
+	rect := image.Rect(lo, lo, hi, hi)
+	switch dir {
+	case grid.Up:
+		rect.Min.Y = 0
+	case grid.Left:
+		rect.Min.X = 0
+	case grid.Right:
+		rect.Max.X = p.cell
+	case grid.Down:
+		rect.Max.Y = p.cell
+	}
+	p.fill(rect.Add(image.Pt(coord.Col*p.cell, coord.Row*p.cell)), loopColor)
+}
+
+// 3x5 bitmap for the start label, since the standard library has no fonts
+var startGlyph = []string{
+	"###",
+	"#..",
+	"###",
+	"..#",
+	"###",
+}
+
+func (p *pngCanvas) Start(coord grid.Coord) {
+	p.Tile(coord, startColor)
+	// Scale the glyph to fit in the tile if there's room, otherwise the colored tile will have to do
+	scale := p.cell / 6
+	if scale == 0 {
+		return
+	}
+	origin := image.Pt(coord.Col*p.cell+(p.cell-3*scale)/2, coord.Row*p.cell+(p.cell-5*scale)/2)
+	for row, line := range startGlyph {
+		for col := range line {
+			if line[col] != '#' {
+				continue
+			}
+			min := origin.Add(image.Pt(col*scale, row*scale))
+			p.fill(image.Rectangle{min, min.Add(image.Pt(scale, scale))}, color.RGBA{0xff, 0xff, 0xff, 0xff})
+		}
+	}
+}
+
+func (m *Map) RenderPNG(w io.Writer, start grid.Coord, cell int) error {
+	p := &pngCanvas{image.NewRGBA(image.Rect(0, 0, m.Cols*cell, m.Rows*cell)), cell}
+	p.fill(p.img.Bounds(), color.RGBA{0xff, 0xff, 0xff, 0xff})
+	m.draw(p, start)
+	return png.Encode(w, p.img)
+}
+
+// Pick the format based on the extension of the path
+func (m *Map) RenderFile(path string, start grid.Coord, cell int) error {
+	var render func(io.Writer, grid.Coord, int) error
+	switch filepath.Ext(path) {
+	case ".svg":
+		render = m.RenderSVG
+	case ".png":
+		render = m.RenderPNG
+	default:
+		return fmt.Errorf("Unknown image format for %v (expected .svg or .png)", path)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return render(file, start, cell)
+}
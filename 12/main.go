@@ -2,11 +2,16 @@ package main
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
+	"iter"
+	"math/big"
 	"os"
 	"slices"
-	"strconv"
 	"strings"
+
+	"github.com/poweredbypie/aoc.2023/parallel"
+	"github.com/poweredbypie/aoc.2023/parse"
 )
 
 type CacheKey struct {
@@ -17,42 +22,43 @@ type CacheKey struct {
 type SpringInfo struct {
 	groups  []int
 	damaged string
+	// The row as it was given, before trimming
+	springs string
 	cache   map[CacheKey]int
 }
 
+// Group sizes like "1,1,3"
+func parseGroups(str string) []int {
+	groups, err := parse.IntsSep(str, ",")
+	if err != nil {
+		panic(err)
+	}
+	return groups
+}
+
 func NewInfo(line string) *SpringInfo {
 	split := strings.Split(line, " ")
 	// Beginning and ending dots are basically useless to us
 	damaged := strings.Trim(split[0], ".")
-	groups := []int{}
-	for _, val := range strings.Split(split[1], ",") {
-		num, _ := strconv.Atoi(val)
-		groups = append(groups, num)
-	}
+	groups := parseGroups(split[1])
 	return &SpringInfo{
 		groups:  groups,
 		damaged: damaged,
+		springs: split[0],
 		cache:   make(map[CacheKey]int),
 	}
 }
 
-func NewUnfoldedInfo(line string) *SpringInfo {
+// Unfold the row into copies of itself, with sep between each copy of the springs.
+// The puzzle uses 5 copies separated by '?'.
+func NewUnfoldedInfo(line string, copies int, sep string) *SpringInfo {
 	split := strings.Split(line, " ")
-	// Disgusting
-	join5 := func(str string, sep string) string {
-		arr := []string{str, str, str, str, str}
-		return strings.Join(arr, sep)
-	}
-	damaged := join5(split[0], "?")
-	groups := []int{}
-	// EW
-	for _, val := range strings.Split(join5(split[1], ","), ",") {
-		num, _ := strconv.Atoi(val)
-		groups = append(groups, num)
-	}
+	damaged := strings.Join(slices.Repeat([]string{split[0]}, copies), sep)
+	groups := parseGroups(split[1])
 	return &SpringInfo{
-		groups:  groups,
+		groups:  slices.Repeat(groups, copies),
 		damaged: damaged,
+		springs: damaged,
 		cache:   make(map[CacheKey]int),
 	}
 }
@@ -150,20 +156,179 @@ func (s *SpringInfo) CountRecur(buf []rune, grpIdx int) int {
 	return sum
 }
 
+// Same as CountRecur, but bottom-up so nothing needs to be mutated or restored.
+// counts[idx][grpIdx] is the number of ways to fit groups[grpIdx:] into damaged[idx:].
+// Generic so rows unfolded enough to overflow an int can count with *big.Int. add is allowed to reuse its first argument.
+func fillCounts[N any](s *SpringInfo, runs []int, zero, one func() N, add func(N, N) N) [][]N {
+	n, g := len(s.damaged), len(s.groups)
+	counts := make([][]N, n+1)
+	for idx := range counts {
+		counts[idx] = make([]N, g+1)
+		for grpIdx := range counts[idx] {
+			counts[idx][grpIdx] = zero()
+		}
+	}
+	// Base case: no groups left, which only works if there's no '#' left either
+	counts[n][g] = one()
+	for idx := n - 1; idx >= 0 && s.damaged[idx] != '#'; idx -= 1 {
+		counts[idx][g] = one()
+	}
+	for idx := n - 1; idx >= 0; idx -= 1 {
+		for grpIdx := g - 1; grpIdx >= 0; grpIdx -= 1 {
+			sum := counts[idx][grpIdx]
+			// Leave this spring working and move on
+			if s.damaged[idx] != '#' {
+				sum = add(sum, counts[idx+1][grpIdx])
+			}
+			// Start the group here, as long as it fits and the separator after it can be a '.'
+			if s.fits(runs, idx, grpIdx) {
+				sum = add(sum, counts[min(idx+s.groups[grpIdx]+1, n)][grpIdx+1])
+			}
+			counts[idx][grpIdx] = sum
+		}
+	}
+	return counts
+}
+
+func (s *SpringInfo) countTable(runs []int) [][]int {
+	return fillCounts(s, runs,
+		func() int { return 0 },
+		func() int { return 1 },
+		func(sum, val int) int { return sum + val })
+}
+
+// How many springs starting at each index could be broken ('#' or '?') in a row
+func (s *SpringInfo) runs() []int {
+	runs := make([]int, len(s.damaged)+1)
+	for idx := len(s.damaged) - 1; idx >= 0; idx -= 1 {
+		if s.damaged[idx] != '.' {
+			runs[idx] = runs[idx+1] + 1
+		}
+	}
+	return runs
+}
+
+func (s *SpringInfo) fits(runs []int, idx, grpIdx int) bool {
+	end := idx + s.groups[grpIdx]
+	return runs[idx] >= s.groups[grpIdx] && (end == len(s.damaged) || s.damaged[end] != '#')
+}
+
+func (s *SpringInfo) CountDP() int {
+	return s.countTable(s.runs())[0][0]
+}
+
+// Same as CountDP, but for rows unfolded enough to overflow an int
+func (s *SpringInfo) CountBig() *big.Int {
+	counts := fillCounts(s, s.runs(),
+		func() *big.Int { return new(big.Int) },
+		func() *big.Int { return big.NewInt(1) },
+		func(sum, val *big.Int) *big.Int { return sum.Add(sum, val) })
+	return counts[0][0]
+}
+
+// Every concrete arrangement of the row, stopping after limit of them if limit > 0.
+// Dots trimmed by NewInfo are put back so each one lines up with the input row.
+func (s *SpringInfo) Arrangements(limit int) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		runs := s.runs()
+		counts := s.countTable(runs)
+		n, g := len(s.damaged), len(s.groups)
+		buf := []byte(s.damaged)
+		lead := strings.Index(s.springs, s.damaged)
+		before, after := s.springs[:lead], s.springs[lead+n:]
+		yielded := 0
+		// Same choices as countTable, but only going down branches that have arrangements in them
+		var walk func(idx, grpIdx int) bool
+		walk = func(idx, grpIdx int) bool {
+			if idx >= n || grpIdx == g {
+				// Anything left over has to be working
+				for pos := idx; pos < n; pos += 1 {
+					buf[pos] = '.'
+				}
+				yielded += 1
+				return yield(before+string(buf)+after) && (limit <= 0 || yielded < limit)
+			}
+			if s.fits(runs, idx, grpIdx) {
+				next := min(idx+s.groups[grpIdx]+1, n)
+				if counts[next][grpIdx+1] > 0 {
+					for pos := idx; pos < next; pos += 1 {
+						buf[pos] = '.'
+					}
+					for pos := idx; pos < idx+s.groups[grpIdx]; pos += 1 {
+						buf[pos] = '#'
+					}
+					if !walk(next, grpIdx+1) {
+						return false
+					}
+				}
+			}
+			if s.damaged[idx] != '#' && counts[idx+1][grpIdx] > 0 {
+				buf[idx] = '.'
+				return walk(idx+1, grpIdx)
+			}
+			return true
+		}
+		if counts[0][0] > 0 {
+			walk(0, 0)
+		}
+	}
+}
+
 func (s *SpringInfo) GetComboCount() int {
-	return s.CountRecur([]rune(s.damaged), 0)
+	return s.CountDP()
 }
 
 func main() {
-	file, _ := os.Open("input")
+	show := flag.Int("show", 0, "print up to this many arrangements for every row instead of solving")
+	input := flag.String("input", "input", "file to read rows from")
+	copies := flag.Int("copies", 5, "how many times to unfold each row")
+	sep := flag.String("sep", "?", "what to put between each copy of the springs when unfolding")
+	growth := flag.Int("growth", 0, "report how the count grows for every row when unfolded 1 to this many times instead of solving")
+	flag.Parse()
+	file, _ := os.Open(*input)
 	defer file.Close()
 	scan := bufio.NewScanner(file)
-	sum := 0
-	unfoldSum := 0
+	if *show > 0 {
+		for scan.Scan() {
+			info := NewInfo(scan.Text())
+			fmt.Printf("%v (%v arrangements)\n", scan.Text(), info.GetComboCount())
+			for arrangement := range info.Arrangements(*show) {
+				fmt.Printf("  %v\n", arrangement)
+			}
+		}
+		return
+	}
+	if *growth > 0 {
+		for scan.Scan() {
+			line := scan.Text()
+			fmt.Printf("%v\n", line)
+			last := new(big.Int)
+			for k := 1; k <= *growth; k += 1 {
+				count := NewUnfoldedInfo(line, k, *sep).CountBig()
+				// How much bigger each extra copy makes the count
+				ratio := "-"
+				if last.Sign() > 0 {
+					ratio = new(big.Rat).SetFrac(count, last).FloatString(2)
+				}
+				fmt.Printf("  k=%v: %v (x%v)\n", k, count, ratio)
+				last = count
+			}
+		}
+		return
+	}
+	lines := []string{}
 	for scan.Scan() {
-		line := scan.Text()
-		sum += NewInfo(line).GetComboCount()
-		unfoldSum += NewUnfoldedInfo(line).GetComboCount()
+		lines = append(lines, scan.Text())
+	}
+	// Every row has its own cache, so they can all be counted at the same time
+	sum := parallel.Sum(lines, func(line string) int {
+		return NewInfo(line).GetComboCount()
+	})
+	unfoldSum := new(big.Int)
+	for _, count := range parallel.Map(lines, func(line string) *big.Int {
+		return NewUnfoldedInfo(line, *copies, *sep).CountBig()
+	}) {
+		unfoldSum.Add(unfoldSum, count)
 	}
 	fmt.Printf("Sum of all combinations for all lines is %v\n", sum)
 	fmt.Printf("Sum of all combinations for all unfolded lines is %v\n", unfoldSum)
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Rows from the puzzle description, with their counts before and after unfolding
+var examples = []struct {
+	line     string
+	count    int
+	unfolded int
+}{
+	{"???.### 1,1,3", 1, 1},
+	{".??..??...?##. 1,1,3", 4, 16384},
+	{"?#?#?#?#?#?#?#? 1,3,1,6", 1, 1},
+	{"????.#...#... 4,1,1", 1, 16},
+	{"????.######..#####. 1,6,5", 4, 2500},
+	{"?###???????? 3,2,1", 10, 506250},
+}
+
+func TestExamples(t *testing.T) {
+	sum, unfoldSum := 0, 0
+	for _, example := range examples {
+		count := NewInfo(example.line).GetComboCount()
+		unfolded := NewUnfoldedInfo(example.line, 5, "?").GetComboCount()
+		if count != example.count {
+			t.Errorf("%v: got %v arrangements, expected %v", example.line, count, example.count)
+		}
+		if unfolded != example.unfolded {
+			t.Errorf("%v: got %v arrangements unfolded, expected %v", example.line, unfolded, example.unfolded)
+		}
+		if big := NewUnfoldedInfo(example.line, 5, "?").CountBig(); !big.IsInt64() || big.Int64() != int64(example.unfolded) {
+			t.Errorf("%v: CountBig got %v unfolded, expected %v", example.line, big, example.unfolded)
+		}
+		sum += count
+		unfoldSum += unfolded
+	}
+	if sum != 21 || unfoldSum != 525152 {
+		t.Errorf("Got sums %v and %v, expected 21 and 525152", sum, unfoldSum)
+	}
+}
+
+func TestCountDPMatchesRecur(t *testing.T) {
+	rng := rand.New(rand.NewPCG(2023, 12))
+	for range 2000 {
+		springs := make([]byte, rng.IntN(20)+1)
+		for idx := range springs {
+			springs[idx] = ".#?"[rng.IntN(3)]
+		}
+		groups := make([]string, rng.IntN(4)+1)
+		for idx := range groups {
+			groups[idx] = strconv.Itoa(rng.IntN(4) + 1)
+		}
+		line := string(springs) + " " + strings.Join(groups, ",")
+		info := NewInfo(line)
+		count := info.CountDP()
+		if recur := info.CountRecur([]rune(info.damaged), 0); recur != count {
+			t.Errorf("%v: CountDP got %v, CountRecur got %v", line, count, recur)
+		}
+		listed := 0
+		for range info.Arrangements(0) {
+			listed += 1
+		}
+		if listed != count {
+			t.Errorf("%v: CountDP got %v, Arrangements listed %v", line, count, listed)
+		}
+	}
+}
+
+func TestArrangementsLineUp(t *testing.T) {
+	for _, example := range examples {
+		springs, _, _ := strings.Cut(example.line, " ")
+		for arrangement := range NewInfo(example.line).Arrangements(0) {
+			if len(arrangement) != len(springs) {
+				t.Errorf("%v: arrangement %v doesn't line up with the row", example.line, arrangement)
+			}
+			for idx := range springs {
+				if springs[idx] != '?' && springs[idx] != arrangement[idx] {
+					t.Errorf("%v: arrangement %v changes a known spring at %v", example.line, arrangement, idx)
+				}
+			}
+		}
+	}
+}
@@ -2,90 +2,257 @@ package main
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
+	"math/bits"
 	"os"
+
+	"github.com/poweredbypie/aoc.2023/grid"
 )
 
 type Pattern struct {
-	lines []string
+	*grid.Grid
+	// Every row and column as a bitmask ('#' is set), so comparing two of them is just XOR and popcount
+	rowBits []Bits
+	colBits []Bits
 }
 
-func (p *Pattern) ReflectValue(dist int) int {
-	rowDist := func(row1, row2 int) int {
-		dist := 0
-		for col := 0; col < len(p.lines[row1]); col += 1 {
-			if p.lines[row1][col] != p.lines[row2][col] {
-				dist += 1
-			}
+// Bitmask for a row or column of any length, 64 cells per word
+type Bits []uint64
+
+func NewBits(size int, set func(idx int) bool) Bits {
+	mask := make(Bits, (size+63)/64)
+	for idx := 0; idx < size; idx += 1 {
+		if set(idx) {
+			mask[idx/64] |= 1 << (idx % 64)
 		}
-		return dist
 	}
-	reflectRowDist := func(row int) int {
-		// Check in both directions
-		dist := 0
-		for iter := 0; row+iter+1 < len(p.lines) && row-iter >= 0; iter += 1 {
-			dist += rowDist(row+iter+1, row-iter)
+	return mask
+}
+
+// Number of cells that differ
+func (b Bits) Dist(other Bits) int {
+	dist := 0
+	for idx := range b {
+		dist += bits.OnesCount64(b[idx] ^ other[idx])
+	}
+	return dist
+}
+
+// Indexes of the cells that differ
+func (b Bits) Diff(other Bits) []int {
+	diff := []int{}
+	for idx := range b {
+		for word := b[idx] ^ other[idx]; word != 0; word &= word - 1 {
+			diff = append(diff, idx*64+bits.TrailingZeros64(word))
 		}
-		return dist
 	}
-	colDist := func(col1, col2 int) int {
-		dist := 0
-		for row := 0; row < len(p.lines); row += 1 {
-			if p.lines[row][col1] != p.lines[row][col2] {
-				dist += 1
+	return diff
+}
+
+type Axis int
+
+const (
+	// Mirror sits between two rows
+	Horizontal Axis = iota
+	// Mirror sits between two columns
+	Vertical
+	// Mirror from the top left to the bottom right (square patterns only)
+	MainDiagonal
+	// Mirror from the top right to the bottom left (square patterns only)
+	AntiDiagonal
+	// Same after turning it upside down
+	Rotate180
+	// Same after turning it a quarter (square patterns only)
+	Rotate90
+)
+
+func (a Axis) Format(f fmt.State, _ rune) {
+	text := "Unknown"
+	switch a {
+	case Horizontal:
+		text = "Horizontal"
+	case Vertical:
+		text = "Vertical"
+	case MainDiagonal:
+		text = "Main diagonal"
+	case AntiDiagonal:
+		text = "Anti-diagonal"
+	case Rotate180:
+		text = "180° rotational"
+	case Rotate90:
+		text = "90° rotational"
+	}
+	f.Write([]byte(text))
+}
+
+// Two cells that should be the same for a reflection but aren't; fixing either one removes the mismatch
+type Smudge struct {
+	One, Two grid.Coord
+}
+
+type Reflection struct {
+	Axis Axis
+	// Row or column right before the mirror
+	Index   int
+	Smudges []Smudge
+}
+
+func (r Reflection) Value() int {
+	if r.Axis == Horizontal {
+		return (r.Index + 1) * 100
+	}
+	return r.Index + 1
+}
+
+func (p *Pattern) axisBits(axis Axis) []Bits {
+	if axis == Horizontal {
+		return p.rowBits
+	}
+	return p.colBits
+}
+
+// Total mismatches for the mirror after index, giving up early once we're past limit
+func (p *Pattern) mirrorDist(axis Axis, index int, limit int) int {
+	lines := p.axisBits(axis)
+	dist := 0
+	// Check in both directions
+	for iter := 0; index+iter+1 < len(lines) && index-iter >= 0 && dist <= limit; iter += 1 {
+		dist += lines[index-iter].Dist(lines[index+iter+1])
+	}
+	return dist
+}
+
+func (p *Pattern) mirrorAt(axis Axis, index int) Reflection {
+	lines := p.axisBits(axis)
+	refl := Reflection{axis, index, []Smudge{}}
+	for iter := 0; index+iter+1 < len(lines) && index-iter >= 0; iter += 1 {
+		one, two := index-iter, index+iter+1
+		for _, cell := range lines[one].Diff(lines[two]) {
+			if axis == Horizontal {
+				refl.Smudges = append(refl.Smudges, Smudge{grid.Coord{Row: one, Col: cell}, grid.Coord{Row: two, Col: cell}})
+			} else {
+				refl.Smudges = append(refl.Smudges, Smudge{grid.Coord{Row: cell, Col: one}, grid.Coord{Row: cell, Col: two}})
 			}
 		}
-		return dist
 	}
-	reflectColDist := func(col int) int {
-		dist := 0
-		for iter := 0; col+iter+1 < len(p.lines[0]) && col-iter >= 0; iter += 1 {
-			dist += colDist(col+iter+1, col-iter)
+	return refl
+}
+
+// Every possible mirror position (rows first, then columns) and what doesn't match for each
+func (p *Pattern) Reflections() []Reflection {
+	refls := []Reflection{}
+	for _, axis := range []Axis{Horizontal, Vertical} {
+		for index := 0; index < len(p.axisBits(axis))-1; index += 1 {
+			refls = append(refls, p.mirrorAt(axis, index))
 		}
-		return dist
 	}
-	for i := 0; i < len(p.lines)-1; i += 1 {
-		if reflectRowDist(i) == dist {
-			// log.Printf("Row %v has a distance of 1 from equivalent", i)
-			return (i + 1) * 100
+	return refls
+}
+
+// How to pick when more than one mirror has the right number of smudges
+type Policy int
+
+const (
+	// Take the first one, rows before columns (what the puzzle wants)
+	First Policy = iota
+	// Fail if there's more than one
+	Unique
+)
+
+func (p *Pattern) Reflect(dist int, policy Policy) (Reflection, error) {
+	// Only count here and find the actual smudges for the ones that match
+	matches := []Reflection{}
+	for _, axis := range []Axis{Horizontal, Vertical} {
+		for index := 0; index < len(p.axisBits(axis))-1; index += 1 {
+			if p.mirrorDist(axis, index, dist) == dist {
+				matches = append(matches, p.mirrorAt(axis, index))
+			}
 		}
 	}
-	for i := 0; i < len(p.lines[0])-1; i += 1 {
-		if reflectColDist(i) == dist {
-			// log.Printf("Col %v has a distance of 1 from equivalent", i)
-			return i + 1
-		}
+	switch {
+	case len(matches) == 0:
+		return Reflection{}, fmt.Errorf("No reflection found with %v smudges", dist)
+	case len(matches) > 1 && policy == Unique:
+		return Reflection{}, fmt.Errorf("%v reflections found with %v smudges", len(matches), dist)
+	default:
+		return matches[0], nil
+	}
+}
+
+func (p *Pattern) ReflectValue(dist int) int {
+	refl, err := p.Reflect(dist, First)
+	if err != nil {
+		panic(err)
 	}
-	panic("No reflection found")
+	return refl.Value()
 }
 
 func NewPattern(scan *bufio.Scanner) *Pattern {
-	lines := []string{}
-	for scan.Scan() {
-		line := scan.Text()
-		if line == "" {
-			break
+	g := grid.Read(scan)
+	if g.Rows == 0 {
+		return nil
+	}
+	return NewPatternFrom(g)
+}
+
+func NewPatternFrom(g *grid.Grid) *Pattern {
+	p := &Pattern{Grid: g}
+	isRock := func(line []byte) func(int) bool {
+		return func(idx int) bool {
+			return line[idx] == '#'
 		}
-		lines = append(lines, line)
 	}
-	if len(lines) == 0 {
-		return nil
+	for row := 0; row < g.Rows; row += 1 {
+		p.rowBits = append(p.rowBits, NewBits(g.Cols, isRock(g.Row(row))))
+	}
+	for col := 0; col < g.Cols; col += 1 {
+		p.colBits = append(p.colBits, NewBits(g.Rows, isRock(g.Col(col))))
 	}
-	return &Pattern{lines}
+	return p
 }
 
 func main() {
+	report := flag.Int("report", -1, "list every mirror with at most this many smudges for each pattern")
+	symmetry := flag.Int("symmetry", -1, "list diagonal and rotational symmetries with at most this many smudges for each pattern")
+	unique := flag.Bool("unique", false, "skip patterns with more than one matching mirror instead of taking the first")
+	flag.Parse()
+	policy := First
+	if *unique {
+		policy = Unique
+	}
+	value := func(idx int, pattern *Pattern, dist int) int {
+		refl, err := pattern.Reflect(dist, policy)
+		if err != nil {
+			fmt.Printf("Skipping pattern %v: %v\n", idx, err)
+			return 0
+		}
+		return refl.Value()
+	}
 	file, _ := os.Open("input")
 	scan := bufio.NewScanner(file)
 	sumPerf := 0
 	sumSmudge := 0
-	for {
+	for idx := 0; ; idx += 1 {
 		pattern := NewPattern(scan)
 		if pattern == nil {
 			break
 		}
-		sumPerf += pattern.ReflectValue(0)
-		sumSmudge += pattern.ReflectValue(1)
+		if *report >= 0 {
+			fmt.Printf("Pattern %v\n", idx)
+			for _, refl := range pattern.Reflections() {
+				if len(refl.Smudges) <= *report {
+					fmt.Printf("  %v mirror after %v: %v smudges %v\n", refl.Axis, refl.Index, len(refl.Smudges), refl.Smudges)
+				}
+			}
+		}
+		if *symmetry >= 0 {
+			for _, sym := range pattern.Symmetries(*symmetry) {
+				fmt.Printf("Pattern %v has %v symmetry with %v smudges %v\n", idx, sym.Axis, len(sym.Smudges), sym.Smudges)
+			}
+		}
+		sumPerf += value(idx, pattern, 0)
+		sumSmudge += value(idx, pattern, 1)
 	}
 	fmt.Printf("Sum of reflect values is %v\n", sumPerf)
 	fmt.Printf("Sum of reflect values with 1 smudge is %v\n", sumSmudge)
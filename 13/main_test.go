@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/poweredbypie/aoc.2023/grid"
+)
+
+// Square pattern with a mirror after a random row and one smudge somewhere in the reflection
+func generate(rng *rand.Rand, size int) *Pattern {
+	lines := make([][]byte, size)
+	for row := range lines {
+		lines[row] = make([]byte, size)
+		for col := range lines[row] {
+			lines[row][col] = ".#"[rng.IntN(2)]
+		}
+	}
+	// Copy rows across the mirror
+	mirror := rng.IntN(size - 1)
+	for iter := 0; mirror+iter+1 < size && mirror-iter >= 0; iter += 1 {
+		copy(lines[mirror+iter+1], lines[mirror-iter])
+	}
+	smudge := &lines[mirror+1][rng.IntN(size)]
+	if *smudge == '#' {
+		*smudge = '.'
+	} else {
+		*smudge = '#'
+	}
+	return NewPatternFrom(grid.New(lines))
+}
+
+func generateMany(size, count int) []*Pattern {
+	rng := rand.New(rand.NewPCG(2023, 13))
+	patterns := []*Pattern{}
+	for range count {
+		patterns = append(patterns, generate(rng, size))
+	}
+	return patterns
+}
+
+// Mismatches for every mirror, comparing characters one at a time like we used to
+func (p *Pattern) charDists() []int {
+	dists := []int{}
+	rows, cols := p.Rows, p.Cols
+	for row := 0; row < rows-1; row += 1 {
+		dist := 0
+		for iter := 0; row+iter+1 < rows && row-iter >= 0; iter += 1 {
+			for col := 0; col < cols; col += 1 {
+				if p.Lines[row-iter][col] != p.Lines[row+iter+1][col] {
+					dist += 1
+				}
+			}
+		}
+		dists = append(dists, dist)
+	}
+	for col := 0; col < cols-1; col += 1 {
+		dist := 0
+		for iter := 0; col+iter+1 < cols && col-iter >= 0; iter += 1 {
+			for row := 0; row < rows; row += 1 {
+				if p.Lines[row][col-iter] != p.Lines[row][col+iter+1] {
+					dist += 1
+				}
+			}
+		}
+		dists = append(dists, dist)
+	}
+	return dists
+}
+
+// Same as charDists, but with the bitmasks
+func (p *Pattern) bitDists() []int {
+	dists := []int{}
+	for _, axis := range []Axis{Horizontal, Vertical} {
+		for index := 0; index < len(p.axisBits(axis))-1; index += 1 {
+			dists = append(dists, p.mirrorDist(axis, index, p.Rows*p.Cols))
+		}
+	}
+	return dists
+}
+
+// Sizes of patterns to try, from puzzle sized to bigger than one uint64
+var sizes = []int{17, 64, 200}
+
+func TestBitsMatchChars(t *testing.T) {
+	for _, size := range sizes {
+		for idx, pattern := range generateMany(size, 20) {
+			chars, bits := pattern.charDists(), pattern.bitDists()
+			if !slices.Equal(chars, bits) {
+				t.Errorf("%vx%v pattern %v: characters say %v, bitmasks say %v", size, size, idx, chars, bits)
+			}
+			if _, err := pattern.Reflect(1, First); err != nil {
+				t.Errorf("%vx%v pattern %v: %v", size, size, idx, err)
+			}
+		}
+	}
+}
+
+func benchDists(b *testing.B, dists func(*Pattern) []int) {
+	for _, size := range sizes {
+		patterns := generateMany(size, 20)
+		b.Run(fmt.Sprintf("%vx%v", size, size), func(b *testing.B) {
+			for idx := range b.N {
+				dists(patterns[idx%len(patterns)])
+			}
+		})
+	}
+}
+
+func BenchmarkCharDists(b *testing.B) {
+	benchDists(b, (*Pattern).charDists)
+}
+
+func BenchmarkBitDists(b *testing.B) {
+	benchDists(b, (*Pattern).bitDists)
+}
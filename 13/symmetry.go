@@ -0,0 +1,95 @@
+package main
+
+import "github.com/poweredbypie/aoc.2023/grid"
+
+// Stuff for symmetries other than plain horizontal/vertical mirrors
+type Symmetry struct {
+	Axis Axis
+	// One entry per cell that has to change.
+	// For Rotate90, One is the odd cell out and Two is a cell it should match.
+	Smudges []Smudge
+}
+
+// Check every cell against its partner, counting each pair once
+func (p *Pattern) pairSmudges(partner func(grid.Coord) grid.Coord) []Smudge {
+	smudges := []Smudge{}
+	for c := range p.All() {
+		other := partner(c)
+		// Only look at pairs where we come first; this also skips cells that are their own partner
+		before := c.Row < other.Row || (c.Row == other.Row && c.Col < other.Col)
+		if before && *p.At(c) != *p.At(other) {
+			smudges = append(smudges, Smudge{c, other})
+		}
+	}
+	return smudges
+}
+
+// Cells under a quarter turn come in groups of 4 (or just the center);
+// every cell that doesn't match the most common value in its group is a smudge
+func (p *Pattern) quarterSmudges() []Smudge {
+	size := p.Rows
+	turn := func(c grid.Coord) grid.Coord {
+		return grid.Coord{Row: c.Col, Col: size - 1 - c.Row}
+	}
+	smudges := []Smudge{}
+	// The top left quadrant (rounded so odd sizes are covered) hits every group exactly once
+	c := grid.Coord{}
+	for c.Row = 0; c.Row < size/2; c.Row += 1 {
+		for c.Col = 0; c.Col < (size+1)/2; c.Col += 1 {
+			group := []grid.Coord{c}
+			for len(group) < 4 {
+				group = append(group, turn(group[len(group)-1]))
+			}
+			at := func(c grid.Coord) byte {
+				return *p.At(c)
+			}
+			// Majority value wins, ties go to the first cell
+			majority := group[0]
+			for _, cell := range group {
+				count := 0
+				for _, other := range group {
+					if at(other) == at(cell) {
+						count += 1
+					}
+				}
+				if count > 2 {
+					majority = cell
+				}
+			}
+			for _, cell := range group {
+				if at(cell) != at(majority) {
+					smudges = append(smudges, Smudge{cell, majority})
+				}
+			}
+		}
+	}
+	return smudges
+}
+
+// Every diagonal or rotational symmetry with at most tolerance smudges
+func (p *Pattern) Symmetries(tolerance int) []Symmetry {
+	rows, cols := p.Rows, p.Cols
+	candidates := []Symmetry{
+		{Rotate180, p.pairSmudges(func(c grid.Coord) grid.Coord {
+			return grid.Coord{Row: rows - 1 - c.Row, Col: cols - 1 - c.Col}
+		})},
+	}
+	if rows == cols {
+		candidates = append(candidates,
+			Symmetry{MainDiagonal, p.pairSmudges(func(c grid.Coord) grid.Coord {
+				return grid.Coord{Row: c.Col, Col: c.Row}
+			})},
+			Symmetry{AntiDiagonal, p.pairSmudges(func(c grid.Coord) grid.Coord {
+				return grid.Coord{Row: cols - 1 - c.Col, Col: rows - 1 - c.Row}
+			})},
+			Symmetry{Rotate90, p.quarterSmudges()},
+		)
+	}
+	syms := []Symmetry{}
+	for _, sym := range candidates {
+		if len(sym.Smudges) <= tolerance {
+			syms = append(syms, sym)
+		}
+	}
+	return syms
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"image/gif"
+	"os"
+)
+
+// Stuff for animating the platform as it gets tilted
+var rockPalette = color.Palette{
+	color.RGBA{0xf4, 0xf1, 0xe8, 0xff},
+	color.RGBA{0x40, 0x40, 0x48, 0xff},
+	color.RGBA{0xc8, 0x6a, 0x20, 0xff},
+}
+
+func paletteIndex(char byte) uint8 {
+	switch char {
+	case '#':
+		return 1
+	case 'O':
+		return 2
+	default:
+		return 0
+	}
+}
+
+type Animation struct {
+	// In 100ths of a second, like image/gif wants
+	delay int
+	// Pixels per side for each slot
+	cell int
+	gif  gif.GIF
+}
+
+func NewAnimation(delay, cell int) *Animation {
+	return &Animation{delay: delay, cell: cell}
+}
+
+// Add the current state of the rocks as a frame
+func (a *Animation) Add(r *Rocks) {
+	frame := image.NewPaletted(image.Rect(0, 0, r.Cols*a.cell, r.Rows*a.cell), rockPalette)
+	for row, slot := range r.Lines {
+		for col, char := range slot {
+			idx := paletteIndex(char)
+			for y := row * a.cell; y < (row+1)*a.cell; y += 1 {
+				for x := col * a.cell; x < (col+1)*a.cell; x += 1 {
+					frame.SetColorIndex(x, y, idx)
+				}
+			}
+		}
+	}
+	a.gif.Image = append(a.gif.Image, frame)
+	a.gif.Delay = append(a.gif.Delay, a.delay)
+}
+
+func (a *Animation) Save(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return gif.EncodeAll(file, &a.gif)
+}
@@ -1,203 +1,299 @@
 package main
 
 import (
-	"bufio"
+	"flag"
 	"fmt"
+	"hash/fnv"
+	"math/bits"
 	"os"
-	"slices"
+
+	"github.com/poweredbypie/aoc.2023/cycle"
+	"github.com/poweredbypie/aoc.2023/grid"
 )
 
+func PanicIf(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
 type Rocks struct {
-	slots [][]byte
+	*grid.Grid
 }
 
 func NewRocks(file *os.File) *Rocks {
-	scan := bufio.NewScanner(file)
-	slots := [][]byte{}
-	for scan.Scan() {
-		slots = append(slots, []byte(scan.Text()))
-	}
-	return &Rocks{slots}
+	return &Rocks{grid.ReadFile(file)}
 }
 
-func inc(val int) int {
-	return val + 1
-}
+type Dir int
+
+const (
+	North Dir = iota
+	West
+	South
+	East
+)
 
-func dec(val int) int {
-	return val - 1
+func (d Dir) Format(f fmt.State, _ rune) {
+	text := "Unknown"
+	switch d {
+	case North:
+		text = "North"
+	case West:
+		text = "West"
+	case South:
+		text = "South"
+	case East:
+		text = "East"
+	}
+	f.Write([]byte(text))
 }
 
-func atLeast(min int) func(int) bool {
-	return func(val int) bool {
-		return val >= min
+func DirFor(char byte) (Dir, error) {
+	switch char {
+	case 'N':
+		return North, nil
+	case 'W':
+		return West, nil
+	case 'S':
+		return South, nil
+	case 'E':
+		return East, nil
+	default:
+		return North, fmt.Errorf("Unexpected direction %c", char)
 	}
 }
 
-func atMost(max int) func(int) bool {
-	return func(val int) bool {
-		return val <= max
+// Tilts to do in order, written like "NWSE"
+func ParseProgram(str string) ([]Dir, error) {
+	program := []Dir{}
+	for idx := 0; idx < len(str); idx += 1 {
+		dir, err := DirFor(str[idx])
+		if err != nil {
+			return nil, err
+		}
+		program = append(program, dir)
 	}
+	return program, nil
 }
 
-// This sucks but it's kind of generic ish
-func (r *Rocks) tiltRock(row, col int, cond func(int) bool, nextRow, nextCol func(int) int) {
-	// Start assuming rows
-	nextIter := nextRow
-	start := row
-	charAt := func(iter int) *byte {
-		return &r.slots[iter][col]
+// Slide every round rock as far as it goes towards dir, in one pass over each column (or row).
+// Walking away from the edge we're tilting towards, we keep track of the next free slot:
+// a '#' moves it to just past the '#', and an 'O' drops into it.
+func (r *Rocks) Tilt(dir Dir) {
+	rows, cols := r.Rows, r.Cols
+	// Lines are what the rocks slide along, pos 0 is the edge they slide towards
+	lines, length := cols, rows
+	if dir == West || dir == East {
+		lines, length = rows, cols
 	}
-	// Otherwise assume columns
-	if nextRow == nil {
-		nextIter = nextCol
-		start = col
-		charAt = func(iter int) *byte {
-			return &r.slots[row][iter]
+	at := func(line, pos int) *byte {
+		switch dir {
+		case North:
+			return &r.Lines[pos][line]
+		case South:
+			return &r.Lines[rows-1-pos][line]
+		case West:
+			return &r.Lines[line][pos]
+		default:
+			return &r.Lines[line][cols-1-pos]
 		}
 	}
-	// Actual loop
-	for iter := start; cond(iter); iter = nextIter(iter) {
-		rock := charAt(iter)
-		next := charAt(nextIter(iter))
-		if *next != '.' {
-			return
+	for line := 0; line < lines; line += 1 {
+		free := 0
+		for pos := 0; pos < length; pos += 1 {
+			switch *at(line, pos) {
+			case '#':
+				free = pos + 1
+			case 'O':
+				*at(line, pos) = '.'
+				*at(line, free) = 'O'
+				free += 1
+			}
 		}
-		*next, *rock = *rock, *next
 	}
 }
 
 func (r *Rocks) TiltNorth() {
-	// Starts at 1 since row 0 is already "tilted"
-	for row := 1; row < len(r.slots); row += 1 {
-		for col := 0; col < len(r.slots[row]); col += 1 {
-			if r.slots[row][col] == 'O' {
-				r.tiltRock(row, col, atLeast(1), dec, nil)
-			}
-		}
-	}
+	r.Tilt(North)
 }
 
 func (r *Rocks) TiltWest() {
-	for col := 1; col < len(r.slots[0]); col += 1 {
-		for row := 0; row < len(r.slots); row += 1 {
-			if r.slots[row][col] == 'O' {
-				r.tiltRock(row, col, atLeast(1), nil, dec)
-			}
-		}
-	}
-	return
+	r.Tilt(West)
 }
 
 func (r *Rocks) TiltSouth() {
-	for row := len(r.slots) - 2; row >= 0; row -= 1 {
-		for col := 0; col < len(r.slots[row]); col += 1 {
-			if r.slots[row][col] == 'O' {
-				r.tiltRock(row, col, atMost(len(r.slots)-2), inc, nil)
-			}
-		}
-	}
-	return
+	r.Tilt(South)
 }
 
 func (r *Rocks) TiltEast() {
-	for col := len(r.slots[0]) - 2; col >= 0; col -= 1 {
-		for row := 0; row < len(r.slots); row += 1 {
-			if r.slots[row][col] == 'O' {
-				r.tiltRock(row, col, atMost(len(r.slots[row])-2), nil, inc)
-			}
-		}
+	r.Tilt(East)
+}
+
+var SpinCycle = []Dir{North, West, South, East}
+
+func (r *Rocks) Run(program []Dir) {
+	for _, dir := range program {
+		r.Tilt(dir)
 	}
-	return
 }
 
 func (r *Rocks) TiltCycle() {
-	r.TiltNorth()
-	r.TiltWest()
-	r.TiltSouth()
-	r.TiltEast()
+	r.Run(SpinCycle)
 }
 
-func (r *Rocks) Load() int {
+// Load a rock puts on edge: 1 for rocks on the far side, up to the full height (or width) for rocks right next to it
+func weight(edge Dir, row, col, rows, cols int) int {
+	switch edge {
+	case North:
+		return rows - row
+	case South:
+		return row + 1
+	case West:
+		return cols - col
+	default:
+		return col + 1
+	}
+}
+
+func (r *Rocks) LoadOn(edge Dir) int {
 	load := 0
-	for row := 0; row < len(r.slots); row += 1 {
-		for col := 0; col < len(r.slots[row]); col += 1 {
-			if r.slots[row][col] == 'O' {
-				load += len(r.slots) - row
-			}
+	for c := range r.All() {
+		if *r.At(c) == 'O' {
+			load += weight(edge, c.Row, c.Col, r.Rows, r.Cols)
 		}
 	}
 	return load
 }
 
+func (r *Rocks) Load() int {
+	return r.LoadOn(North)
+}
+
 func (r *Rocks) Clone() *Rocks {
-	slots := [][]byte{}
-	for _, slot := range r.slots {
-		slots = append(slots, slices.Clone(slot))
-	}
-	return &Rocks{slots}
+	return &Rocks{r.Grid.Clone()}
 }
 
 func (r *Rocks) Equal(other *Rocks) bool {
-	for i := range r.slots {
-		if slices.Compare(r.slots[i], other.slots[i]) != 0 {
-			return false
+	return r.Grid.Equal(other.Grid)
+}
+
+// Run the program without changing r, so states can be kept around for cycle detection
+func (r *Rocks) Then(program []Dir) *Rocks {
+	next := r.Clone()
+	next.Run(program)
+	return next
+}
+
+func (r *Rocks) Spin() *Rocks {
+	return r.Then(SpinCycle)
+}
+
+// Compact snapshot of where the round rocks are; the square ones never move so they don't need to be in here.
+// States can be compared with == and used as map keys.
+type State struct {
+	// FNV-1a of bits, stable between runs
+	Hash uint64
+	// One bit per cell, row by row, set for round rocks
+	bits       string
+	rows, cols int
+}
+
+func (r *Rocks) State() State {
+	rows, cols := r.Rows, r.Cols
+	packed := make([]byte, (rows*cols+7)/8)
+	for c := range r.All() {
+		if *r.At(c) == 'O' {
+			idx := c.Row*cols + c.Col
+			packed[idx/8] |= 1 << (idx % 8)
 		}
 	}
-	return true
+	hash := fnv.New64a()
+	hash.Write(packed)
+	return State{hash.Sum64(), string(packed), rows, cols}
 }
 
-func (r *Rocks) Dump(name string) {
-	file, _ := os.Create(name)
-	for _, slot := range r.slots {
-		file.Write(slot)
-		file.Write([]byte("\n"))
+// Same as Rocks.LoadOn, straight from the packed bits
+func (s State) LoadOn(edge Dir) int {
+	load := 0
+	for idx := 0; idx < len(s.bits); idx += 1 {
+		for word := s.bits[idx]; word != 0; word &= word - 1 {
+			cell := idx*8 + bits.TrailingZeros8(word)
+			load += weight(edge, cell/s.cols, cell%s.cols, s.rows, s.cols)
+		}
+	}
+	return load
+}
+
+func (s State) Load() int {
+	return s.LoadOn(North)
+}
+
+func Detect(method string, start *Rocks, step func(*Rocks) *Rocks) cycle.Cycle {
+	switch method {
+	case "map":
+		return cycle.Find(start, step, (*Rocks).State)
+	case "floyd":
+		return cycle.Floyd(start, step, (*Rocks).Equal)
+	case "brent":
+		return cycle.Brent(start, step, (*Rocks).Equal)
+	default:
+		panic("Unknown cycle detection " + method)
 	}
 }
 
 func main() {
+	detect := flag.String("detect", "map", "how to find the cycle: map, floyd or brent")
+	program := flag.String("program", "", "run these tilts (like \"NWSE\") instead of solving")
+	times := flag.Int("times", 1, "how many times to run -program")
+	edge := flag.String("edge", "N", "edge to measure the load on after -program")
+	anim := flag.String("gif", "", "animate the spin cycles up to the end of the first loop into this file")
+	perTilt := flag.Bool("per-tilt", false, "add a frame for every tilt instead of every spin cycle")
+	delay := flag.Int("delay", 10, "time between frames in 100ths of a second")
+	cell := flag.Int("cell", 4, "size of each slot in pixels")
+	flag.Parse()
 	file, _ := os.Open("input")
 	rocks := NewRocks(file)
+	if *program != "" {
+		tilts, err := ParseProgram(*program)
+		PanicIf(err)
+		edges, err := ParseProgram(*edge)
+		PanicIf(err)
+		if len(edges) != 1 {
+			panic("Expected exactly one edge, got " + *edge)
+		}
+		loadEdge := edges[0]
+		step := func(r *Rocks) *Rocks {
+			return r.Then(tilts)
+		}
+		found := Detect(*detect, rocks, step)
+		final := cycle.After(found, rocks, step, *times)
+		fmt.Printf("Load on %v edge after running %v %v times is %v\n", loadEdge, *program, *times, final.State().LoadOn(loadEdge))
+		return
+	}
+	start := rocks.Clone()
 	rocks.TiltNorth()
 	fmt.Printf("Load on north edge is %v\n", rocks.Load())
-	last := []*Rocks{}
-	// This is THE worst code ever LOL
-	// I have a circuits final in like 13 hours so that's my excuse
-	for i := 0; i < 1_000_000_000; i += 1 {
-		// Tilt cycle
-		rocks.TiltCycle()
-		// Find how many matches exist in the previous tilt cycles
-		matches := []int{}
-		for i := range last {
-			if last[i].Equal(rocks) {
-				matches = append(matches, i)
+	found := Detect(*detect, start, (*Rocks).Spin)
+	fmt.Printf("Cycle starts after %v spins and repeats every %v\n", found.Start, found.Length)
+	if *anim != "" {
+		// Everything after this just repeats
+		frames := NewAnimation(*delay, *cell)
+		spun := start.Clone()
+		frames.Add(spun)
+		for range found.Start + found.Length {
+			for _, dir := range SpinCycle {
+				spun.Tilt(dir)
+				if *perTilt {
+					frames.Add(spun)
+				}
 			}
-		}
-		// Add ourselves to the previous cycles
-		last = append(last, rocks.Clone())
-		// This is very arbitrary and honestly not necessary
-		if len(matches) <= 4 {
-			continue
-		}
-
-		// If we have more than 4 matches calculate the spacing between them
-		// Basically how long a cycle is
-		delta := matches[1] - matches[0]
-
-		// Assert that the spacing is consistent
-		for i := 0; i < len(matches)-1; i += 1 {
-			if matches[i+1]-matches[i] != delta {
-				panic("Delta isn't consistent between matches")
+			if !*perTilt {
+				frames.Add(spun)
 			}
 		}
-
-		// Find the multiplier to get close to 1 billion - 1
-		mult := (999_999_999 - matches[0]) / delta
-		// If the first match plus the multiplier times the delta equals 1 billion - 1
-		// we found our match (its 999 something because we're 0 indexing)
-		if (matches[0] + mult*delta) == 999_999_999 {
-			fmt.Printf("Load after 1 billion cycles is %v\n", rocks.Load())
-			return
-		}
+		PanicIf(frames.Save(*anim))
 	}
+	final := cycle.After(found, start, (*Rocks).Spin, 1_000_000_000)
+	fmt.Printf("Load after 1 billion cycles is %v\n", final.State().Load())
 }
@@ -0,0 +1,16 @@
+package aoc
+
+// Known right answers for the checked-in inputs, keyed by day. Empty means we don't know yet
+var Answers = map[int][2]string{
+	// The Go solution gets 53896 for part B because it misses overlapping words like "oneight"
+	1:  {"53651", "53894"},
+	2:  {"2406", "78375"},
+	3:  {"539637", "82818007"},
+	4:  {"21158", "6050769"},
+	5:  {"382895070", ""},
+	6:  {"393120", "36872656"},
+	10: {"6968", "413"},
+	12: {"7694", "5071883216318"},
+	13: {"34100", "33106"},
+	14: {"109833", "99875"},
+}
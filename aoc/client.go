@@ -0,0 +1,125 @@
+// Package aoc talks to the Advent of Code website.
+package aoc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultBaseURL = "https://adventofcode.com"
+	// The site asks automated tools to say who they are
+	DefaultUserAgent = "github.com/poweredbypie/aoc.2023 by poweredbypie"
+	// Name of the environment variable holding the session cookie
+	SessionEnv = "AOC_SESSION"
+)
+
+type Client struct {
+	// Everything is requested relative to this, so tests can point it at an httptest server
+	BaseURL   string
+	Session   string
+	UserAgent string
+	HTTP      *http.Client
+	// Where downloaded inputs are kept, so each one is only ever downloaded once
+	CacheDir string
+}
+
+// Client with the default base URL and cache directory, for the given session cookie
+func NewClient(session string) (*Client, error) {
+	cache, err := os.UserCacheDir()
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't find a cache directory: %w", err)
+	}
+	return &Client{
+		BaseURL:   DefaultBaseURL,
+		Session:   session,
+		UserAgent: DefaultUserAgent,
+		HTTP:      &http.Client{Timeout: 30 * time.Second},
+		CacheDir:  filepath.Join(cache, "aoc"),
+	}, nil
+}
+
+// Returned when something needs the site but there's no session to log in with
+var ErrNoSession = errors.New("No session found, set $" + SessionEnv + " or write it to aoc/session in your config directory")
+
+// Session cookie from $AOC_SESSION, or from the session file in the user's config directory.
+// Empty if there isn't one, since cached inputs don't need it.
+func FindSession() (string, error) {
+	if session := os.Getenv(SessionEnv); session != "" {
+		return session, nil
+	}
+	config, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("Couldn't find a config directory: %w", err)
+	}
+	path := filepath.Join(config, "aoc", "session")
+	bytes, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(bytes)), nil
+}
+
+func (c *Client) inputPath(year, day int) string {
+	return filepath.Join(c.CacheDir, fmt.Sprint(year), fmt.Sprintf("%02d", day), "input")
+}
+
+// Send a request with the session cookie and user agent attached, failing on anything but a 200
+func (c *Client) do(req *http.Request) ([]byte, error) {
+	slog.Debug("Requesting", "method", req.Method, "url", req.URL)
+	req.Header.Set("User-Agent", c.UserAgent)
+	req.AddCookie(&http.Cookie{Name: "session", Value: c.Session})
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%v %v: %v: %v", req.Method, req.URL, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
+
+// Puzzle input for the day, from the cache if we've downloaded it before
+func (c *Client) Input(year, day int) ([]byte, error) {
+	path := c.inputPath(year, day)
+	if cached, err := os.ReadFile(path); err == nil {
+		slog.Debug("Using cached input", "path", path)
+		return cached, nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if c.Session == "" {
+		return nil, ErrNoSession
+	}
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%v/%v/day/%v/input", c.BaseURL, year, day), nil)
+	if err != nil {
+		return nil, err
+	}
+	input, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	// Write somewhere else first so a failed write never looks like a cached input
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, input, 0o644); err != nil {
+		return nil, err
+	}
+	return input, os.Rename(tmp, path)
+}
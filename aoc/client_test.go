@@ -0,0 +1,80 @@
+package aoc
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+)
+
+// Client with session "secret" pointed at a fake site served by handler, which is shut down after the test
+func testClient(t *testing.T, handler http.Handler) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return &Client{
+		BaseURL:   server.URL,
+		Session:   "secret",
+		UserAgent: DefaultUserAgent,
+		HTTP:      server.Client(),
+		CacheDir:  t.TempDir(),
+	}
+}
+
+// Client pointed at a fake site that serves day 1 and 404s everything else, counting the requests it gets
+func fakeSite(t *testing.T) (*Client, *atomic.Int32) {
+	requests := &atomic.Int32{}
+	client := testClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if agent := r.Header.Get("User-Agent"); agent != DefaultUserAgent {
+			t.Errorf("Got User-Agent %q, expected %q", agent, DefaultUserAgent)
+		}
+		if cookie, err := r.Cookie("session"); err != nil || cookie.Value != "secret" {
+			t.Errorf("Got session cookie %v (%v), expected secret", cookie, err)
+		}
+		if r.URL.Path != "/2023/day/1/input" {
+			http.Error(w, "Not found", http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("1abc2\n"))
+	}))
+	return client, requests
+}
+
+func TestInputCaches(t *testing.T) {
+	client, requests := fakeSite(t)
+	input, err := client.Input(2023, 1)
+	if err != nil || string(input) != "1abc2\n" {
+		t.Fatalf("Got %q (%v), expected the input", input, err)
+	}
+	if cached, err := os.ReadFile(client.inputPath(2023, 1)); err != nil || string(cached) != "1abc2\n" {
+		t.Errorf("Got %q (%v) in the cache, expected the input", cached, err)
+	}
+	// A cached input doesn't need the site, or a session
+	client.Session = ""
+	input, err = client.Input(2023, 1)
+	if err != nil || string(input) != "1abc2\n" {
+		t.Fatalf("Got %q (%v) the second time, expected the input", input, err)
+	}
+	if requests.Load() != 1 {
+		t.Errorf("Made %v requests, expected 1", requests.Load())
+	}
+}
+
+func TestInputErrors(t *testing.T) {
+	client, requests := fakeSite(t)
+	if _, err := client.Input(2023, 2); err == nil {
+		t.Error("Expected an error for a 404")
+	}
+	if _, err := os.Stat(client.inputPath(2023, 2)); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected no cache file after a 404, got %v", err)
+	}
+	client.Session = ""
+	if _, err := client.Input(2023, 3); !errors.Is(err, ErrNoSession) {
+		t.Errorf("Got %v without a session, expected ErrNoSession", err)
+	}
+	if requests.Load() != 1 {
+		t.Errorf("Made %v requests, expected 1", requests.Load())
+	}
+}
@@ -0,0 +1,166 @@
+package aoc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// What one implementation of a day printed
+type Result struct {
+	Lang string
+	// Empty for parts it didn't print
+	Answers [2]string
+	// Set when the language's toolchain isn't installed
+	Skipped bool
+	Err     error
+}
+
+// Most solutions print their answers like "Sum of something is 1234" or "Part A result is: 1234"
+var answerRegex = regexp.MustCompile(`\bis:? +(-?\d+)\s*$`)
+
+// Which parts an implementation prints, for the ones that don't print both in order
+var partsPrinted = map[string][]int{
+	"01/py": {2},
+}
+
+// Write numbers the same way no matter how they were printed, so "007" and "7" agree
+func normalize(answer string) string {
+	num, ok := new(big.Int).SetString(strings.TrimSpace(answer), 10)
+	if !ok {
+		return strings.TrimSpace(answer)
+	}
+	return num.String()
+}
+
+// Pull answers out of the output of a non-Go implementation, in the order they were printed
+func answersIn(output string, parts []int) [2]string {
+	found := []string{}
+	for _, line := range strings.Split(output, "\n") {
+		if match := answerRegex.FindStringSubmatch(line); match != nil {
+			found = append(found, match[1])
+		}
+	}
+	if parts == nil {
+		parts = []int{1, 2}
+	}
+	answers := [2]string{}
+	for idx, part := range parts {
+		if idx < len(found) {
+			answers[part-1] = normalize(found[idx])
+		}
+	}
+	return answers
+}
+
+// Run every step for the language in dir, giving up once timeout passes
+func (l Lang) run(dir string, timeout time.Duration) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	output := bytes.Buffer{}
+	for _, step := range l.Steps(dir) {
+		slog.Debug("Running", "lang", l.Name, "dir", dir, "step", strings.Join(step, " "))
+		cmd := exec.CommandContext(ctx, step[0], step[1:]...)
+		cmd.Dir = dir
+		ownGroup(cmd)
+		// Don't wait forever on output pipes held open by anything the kill missed
+		cmd.WaitDelay = time.Second
+		stderr := bytes.Buffer{}
+		cmd.Stdout = &output
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			if ctx.Err() != nil {
+				return "", fmt.Errorf("%v timed out after %v", strings.Join(step, " "), timeout)
+			}
+			return "", fmt.Errorf("%v: %w\n%v", strings.Join(step, " "), err, strings.TrimSpace(stderr.String()))
+		}
+	}
+	return output.String(), nil
+}
+
+// Run one implementation of the day. Everything but Go is built in a copy of the directory so build output doesn't end up in the repo
+func (l Lang) Result(root string, day int, timeout time.Duration) Result {
+	result := Result{Lang: l.Name}
+	if _, err := exec.LookPath(l.Tool); err != nil {
+		slog.Debug("Skipping", "lang", l.Name, "day", day, "tool", l.Tool)
+		result.Skipped = true
+		return result
+	}
+	dir := filepath.Join(root, DayDir(day))
+	if l.Name == "go" {
+		solver, ok := SolverFor(day)
+		if !ok {
+			result.Err = errors.New("Not registered as a solver")
+			return result
+		}
+		output, err := l.run(dir, timeout)
+		result.Answers, result.Err = solver.Answers(output), err
+		return result
+	}
+
+	tmp, err := os.MkdirTemp("", "aoc-"+l.Name)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	defer os.RemoveAll(tmp)
+	copied := filepath.Join(tmp, DayDir(day))
+	if err := os.CopyFS(copied, os.DirFS(dir)); err != nil {
+		result.Err = err
+		return result
+	}
+	output, err := l.run(copied, timeout)
+	result.Answers, result.Err = answersIn(output, partsPrinted[DayDir(day)+"/"+l.Name]), err
+	return result
+}
+
+// Run every implementation of the day
+func Compare(root string, day int, timeout time.Duration) []Result {
+	dir := filepath.Join(root, DayDir(day))
+	results := []Result{}
+	for _, lang := range Langs {
+		if lang.In(dir) {
+			results = append(results, lang.Result(root, day, timeout))
+		}
+	}
+	return results
+}
+
+// Everything that disagrees about a part, including the known answer if there is one, as "answer: who said it" lines
+func Disagreements(day int, results []Result) map[int][]string {
+	out := map[int][]string{}
+	for part := 1; part <= 2; part += 1 {
+		who := map[string][]string{}
+		order := []string{}
+		add := func(answer, name string) {
+			if _, ok := who[answer]; !ok {
+				order = append(order, answer)
+			}
+			who[answer] = append(who[answer], name)
+		}
+		if known := Answers[day][part-1]; known != "" {
+			add(normalize(known), "known")
+		}
+		for _, result := range results {
+			if answer := result.Answers[part-1]; answer != "" {
+				add(answer, result.Lang)
+			}
+		}
+		if len(order) < 2 {
+			continue
+		}
+		for _, answer := range order {
+			out[part] = append(out[part], fmt.Sprintf("%v: %v", answer, strings.Join(who[answer], ", ")))
+		}
+	}
+	return out
+}
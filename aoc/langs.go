@@ -0,0 +1,98 @@
+package aoc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// A language days get solved in
+type Lang struct {
+	// Short name, used as the column header in the README
+	Name string
+	// Patterns relative to the day directory, any of which existing means the day is solved in this language
+	Files []string
+	// Program that has to be installed to run solutions in this language
+	Tool string
+	// Commands that build and run the solution from inside its directory
+	Steps func(dir string) [][]string
+}
+
+func oneStep(args ...string) func(string) [][]string {
+	return func(string) [][]string {
+		return [][]string{args}
+	}
+}
+
+// Rust days either have one binary or a binary per part
+func cargoSteps(dir string) [][]string {
+	bins, _ := filepath.Glob(filepath.Join(dir, "src", "bin", "*.rs"))
+	if len(bins) == 0 {
+		return [][]string{{"cargo", "run", "--release", "--quiet"}}
+	}
+	sort.Strings(bins)
+	steps := [][]string{}
+	for _, bin := range bins {
+		steps = append(steps, []string{"cargo", "run", "--release", "--quiet", "--bin", strings.TrimSuffix(filepath.Base(bin), ".rs")})
+	}
+	return steps
+}
+
+// In the same order as the README columns
+var Langs = []Lang{
+	{Name: "go", Files: []string{"main.go"}, Tool: "go", Steps: oneStep("go", "run", ".")},
+	{Name: "hs", Files: []string{"Main.hs"}, Tool: "runghc", Steps: oneStep("runghc", "Main.hs")},
+	{Name: "ts", Files: []string{"main.ts"}, Tool: "deno", Steps: oneStep("deno", "run", "--allow-read", "main.ts")},
+	{Name: "c", Files: []string{"main.c"}, Tool: "cc", Steps: func(string) [][]string {
+		return [][]string{{"cc", "-O2", "-o", "main", "main.c", "-lm"}, {"./main"}}
+	}},
+	{Name: "rs", Files: []string{"src/main.rs", "src/bin/*.rs"}, Tool: "cargo", Steps: cargoSteps},
+	{Name: "py", Files: []string{"main.py"}, Tool: "python3", Steps: oneStep("python3", "main.py")},
+	{Name: "cs", Files: []string{"Program.cs"}, Tool: "dotnet", Steps: oneStep("dotnet", "run", "-c", "Release")},
+	{Name: "zig", Files: []string{"main.zig"}, Tool: "zig", Steps: oneStep("zig", "run", "-O", "ReleaseFast", "main.zig")},
+}
+
+func LangFor(name string) (Lang, bool) {
+	for _, lang := range Langs {
+		if lang.Name == name {
+			return lang, true
+		}
+	}
+	return Lang{}, false
+}
+
+// Whether the day in dir has a solution in this language
+func (l Lang) In(dir string) bool {
+	for _, pattern := range l.Files {
+		if found, _ := filepath.Glob(filepath.Join(dir, pattern)); len(found) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Directory a day lives in, relative to the root of the repo
+func DayDir(day int) string {
+	return fmt.Sprintf("%02d", day)
+}
+
+// Every day with a directory under root, in order
+func Days(root string) ([]int, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+	days := []int{}
+	for _, entry := range entries {
+		day, err := strconv.Atoi(entry.Name())
+		if !entry.IsDir() || err != nil || day < 1 || day > 25 || entry.Name() != DayDir(day) {
+			continue
+		}
+		days = append(days, day)
+	}
+	sort.Ints(days)
+	return days, nil
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package aoc
+
+import "os/exec"
+
+// No process groups here, so WaitDelay in Lang.run is all that stops a stuck solver
+func ownGroup(cmd *exec.Cmd) {}
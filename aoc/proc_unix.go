@@ -0,0 +1,17 @@
+//go:build unix
+
+package aoc
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// Run the command in its own process group and kill the whole group when it's cancelled.
+// Otherwise only wrappers like `go run` die, and the solver they started keeps running.
+func ownGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
@@ -0,0 +1,75 @@
+package aoc
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// A day with a Go solution, and how to find its answers in what it prints
+type Solver struct {
+	Day int
+	// Each part's answer is the first word after this on the line that starts with it
+	Parts [2]string
+}
+
+// Every day solved in Go, in order
+var Solvers = []Solver{
+	{1, [2]string{"The sum for part A is", "The sum for part B is"}},
+	{2, [2]string{"Sum of valid games for part A is", "Powersum for part B is"}},
+	{3, [2]string{"Part A sum is", "Part B sum is"}},
+	{4, [2]string{"Part 1 sum is", "Part 2 sum is"}},
+	{5, [2]string{"Part A: minimum mapped value is", "Part B: minimum mapped value is"}},
+	{6, [2]string{"Part A sum is", "Part B single race has"}},
+	{10, [2]string{"Max value for distance is", "Number of unfilled values is"}},
+	{12, [2]string{"Sum of all combinations for all lines is", "Sum of all combinations for all unfolded lines is"}},
+	{13, [2]string{"Sum of reflect values is", "Sum of reflect values with 1 smudge is"}},
+	{14, [2]string{"Load on north edge is", "Load after 1 billion cycles is"}},
+}
+
+func SolverFor(day int) (Solver, bool) {
+	for _, solver := range Solvers {
+		if solver.Day == day {
+			return solver, true
+		}
+	}
+	return Solver{}, false
+}
+
+func (s Solver) Dir() string {
+	return DayDir(s.Day)
+}
+
+// Pull the answers for both parts out of the solver's output. Parts that weren't printed are left empty
+func (s Solver) Answers(output string) [2]string {
+	answers := [2]string{}
+	for _, line := range strings.Split(output, "\n") {
+		for part, prefix := range s.Parts {
+			rest, ok := strings.CutPrefix(line, prefix)
+			if !ok || answers[part] != "" {
+				continue
+			}
+			if fields := strings.Fields(rest); len(fields) > 0 {
+				answers[part] = fields[0]
+			}
+		}
+	}
+	return answers
+}
+
+// Run the solver against the input in its directory and return the answers for both parts
+func (s Solver) Run(root string) ([2]string, error) {
+	cmd := exec.Command("go", "run", ".")
+	cmd.Dir = filepath.Join(root, s.Dir())
+	slog.Debug("Running", "day", s.Day, "dir", cmd.Dir)
+	stderr := bytes.Buffer{}
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return [2]string{}, fmt.Errorf("Running day %v: %w\n%v", s.Day, err, stderr.String())
+	}
+	return s.Answers(string(output)), nil
+}
@@ -0,0 +1,231 @@
+package aoc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// What the site said about an answer
+type Outcome string
+
+const (
+	Correct   Outcome = "correct"
+	Incorrect Outcome = "incorrect"
+	TooHigh   Outcome = "too high"
+	TooLow    Outcome = "too low"
+	// Submitted too soon after the last wrong answer, so it wasn't checked
+	Wait Outcome = "wait"
+	// The part was already solved, so it wasn't checked
+	AlreadySolved Outcome = "already solved"
+	Unknown       Outcome = "unknown"
+)
+
+// Whether the site checked the answer and said no
+func (o Outcome) Wrong() bool {
+	return o == Incorrect || o == TooHigh || o == TooLow
+}
+
+type Response struct {
+	Outcome Outcome
+	// How long to wait before submitting again, if the site said
+	Wait time.Duration
+	// The text of the response, without the rest of the page
+	Message string
+}
+
+var (
+	articleRegex = regexp.MustCompile(`(?s)<article[^>]*>(.*?)</article>`)
+	tagRegex     = regexp.MustCompile(`<[^>]*>`)
+	// Ex: "You have 1m 23s left to wait" or "please wait 5 minutes before trying again"
+	waitLeftRegex   = regexp.MustCompile(`(?:(\d+)m )?(\d+)s left to wait`)
+	waitBeforeRegex = regexp.MustCompile(`wait (one|\d+) minutes? before trying again`)
+)
+
+func parseWait(message string) time.Duration {
+	if found := waitLeftRegex.FindStringSubmatch(message); found != nil {
+		mins, _ := strconv.Atoi(found[1])
+		secs, _ := strconv.Atoi(found[2])
+		return time.Duration(mins)*time.Minute + time.Duration(secs)*time.Second
+	}
+	if found := waitBeforeRegex.FindStringSubmatch(message); found != nil {
+		if found[1] == "one" {
+			return time.Minute
+		}
+		mins, _ := strconv.Atoi(found[1])
+		return time.Duration(mins) * time.Minute
+	}
+	return 0
+}
+
+// Figure out what the page returned after posting an answer means
+func ParseResponse(page string) Response {
+	message := page
+	if found := articleRegex.FindStringSubmatch(page); found != nil {
+		message = found[1]
+	}
+	message = strings.Join(strings.Fields(tagRegex.ReplaceAllString(message, "")), " ")
+	resp := Response{Outcome: Unknown, Wait: parseWait(message), Message: message}
+	switch {
+	case strings.Contains(message, "That's the right answer"):
+		resp.Outcome = Correct
+	case strings.Contains(message, "That's not the right answer"):
+		resp.Outcome = Incorrect
+		if strings.Contains(message, "your answer is too high") {
+			resp.Outcome = TooHigh
+		} else if strings.Contains(message, "your answer is too low") {
+			resp.Outcome = TooLow
+		}
+	case strings.Contains(message, "You gave an answer too recently"):
+		resp.Outcome = Wait
+	case strings.Contains(message, "You don't seem to be solving the right level"):
+		resp.Outcome = AlreadySolved
+	}
+	return resp
+}
+
+// One answer we've submitted before
+type Attempt struct {
+	Year    int
+	Day     int
+	Part    int
+	Answer  string
+	Outcome Outcome
+	Time    time.Time
+	// The site won't check another answer for the day until this
+	NotBefore time.Time
+}
+
+// Every answer we've submitted, kept in a file so we don't make the same mistake twice
+type History struct {
+	Path     string
+	Attempts []Attempt
+}
+
+func (c *Client) historyPath() string {
+	return filepath.Join(c.CacheDir, "history.json")
+}
+
+// Load the history at path, which is empty if the file doesn't exist yet
+func LoadHistory(path string) (*History, error) {
+	history := &History{Path: path}
+	bytes, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return history, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(bytes, &history.Attempts); err != nil {
+		return nil, fmt.Errorf("Couldn't read history from %v: %w", path, err)
+	}
+	return history, nil
+}
+
+func (h *History) Save() error {
+	bytes, err := json.MarshalIndent(h.Attempts, "", "\t")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(h.Path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(h.Path, bytes, 0o644)
+}
+
+func (h *History) Add(attempt Attempt) error {
+	h.Attempts = append(h.Attempts, attempt)
+	return h.Save()
+}
+
+// Error explaining why the answer shouldn't be submitted, or nil if it might be right
+func (h *History) Check(year, day, part int, answer string) error {
+	num, numErr := strconv.Atoi(answer)
+	for _, attempt := range h.Attempts {
+		if attempt.Year != year || attempt.Day != day {
+			continue
+		}
+		// Posting before the wait is up just makes the site reset it
+		if wait := time.Until(attempt.NotBefore); wait > 0 {
+			return fmt.Errorf("Wait %v before submitting again", wait.Round(time.Second))
+		}
+		if attempt.Part != part {
+			continue
+		}
+		if attempt.Outcome == Correct || attempt.Outcome == AlreadySolved {
+			return fmt.Errorf("Part %v was already solved", part)
+		}
+		if attempt.Outcome.Wrong() && attempt.Answer == answer {
+			return fmt.Errorf("%v was already submitted on %v and was %v", answer, attempt.Time.Format(time.DateTime), attempt.Outcome)
+		}
+		// Bounds from earlier answers rule out anything past them too
+		prev, err := strconv.Atoi(attempt.Answer)
+		if numErr != nil || err != nil {
+			continue
+		}
+		if attempt.Outcome == TooHigh && num >= prev {
+			return fmt.Errorf("%v can't be right, %v was already too high", answer, prev)
+		}
+		if attempt.Outcome == TooLow && num <= prev {
+			return fmt.Errorf("%v can't be right, %v was already too low", answer, prev)
+		}
+	}
+	return nil
+}
+
+// Post an answer without checking it against the history
+func (c *Client) Post(year, day, part int, answer string) (Response, error) {
+	form := url.Values{"level": {fmt.Sprint(part)}, "answer": {answer}}
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%v/%v/day/%v/answer", c.BaseURL, year, day), strings.NewReader(form.Encode()))
+	if err != nil {
+		return Response{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	page, err := c.do(req)
+	if err != nil {
+		return Response{}, err
+	}
+	return ParseResponse(string(page)), nil
+}
+
+// Submit an answer unless the history says it's wrong, and remember what the site said about it
+func (c *Client) Submit(year, day, part int, answer string) (Response, error) {
+	if part != 1 && part != 2 {
+		return Response{}, fmt.Errorf("Part must be 1 or 2, not %v", part)
+	}
+	if c.Session == "" {
+		return Response{}, ErrNoSession
+	}
+	history, err := LoadHistory(c.historyPath())
+	if err != nil {
+		return Response{}, err
+	}
+	if err := history.Check(year, day, part, answer); err != nil {
+		return Response{}, err
+	}
+	resp, err := c.Post(year, day, part, answer)
+	if err != nil {
+		return Response{}, err
+	}
+	// Nothing was learned if we can't tell what the site said
+	if resp.Outcome == Unknown {
+		return resp, nil
+	}
+	now := time.Now()
+	return resp, history.Add(Attempt{
+		Year:      year,
+		Day:       day,
+		Part:      part,
+		Answer:    answer,
+		Outcome:   resp.Outcome,
+		Time:      now,
+		NotBefore: now.Add(resp.Wait),
+	})
+}
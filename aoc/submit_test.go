@@ -0,0 +1,108 @@
+package aoc
+
+import (
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Wrap a message the way the site does
+func page(message string) string {
+	return `<!DOCTYPE html><html lang="en-us"><head><title>Day 1 - Advent of Code 2023</title></head><body>` +
+		`<header><h1 class="title-global"><a href="/">Advent of Code</a></h1></header>` +
+		`<main><article><p>` + message + `</p></article></main></body></html>`
+}
+
+const stuck = `If you're stuck, make sure you're using the full input data; there are also some general tips on the ` +
+	`<a href="/2023/about">about page</a>, or you can ask for hints on the ` +
+	`<a href="https://www.reddit.com/r/adventofcode/" target="_blank">subreddit</a>.`
+
+func TestParseResponse(t *testing.T) {
+	cases := []struct {
+		name    string
+		message string
+		outcome Outcome
+		wait    time.Duration
+	}{
+		{"correct", `That's the right answer!  You are <span class="day-success">one gold star</span> closer to restoring snow operations. <a href="/2023/day/1#part2">[Continue to Part Two]</a>`, Correct, 0},
+		{"incorrect", `That's not the right answer.  ` + stuck + `  Please wait one minute before trying again. <a href="/2023/day/1">[Return to Day 1]</a>`, Incorrect, time.Minute},
+		{"too high", `That's not the right answer; your answer is too high.  ` + stuck + `  Please wait one minute before trying again. <a href="/2023/day/1">[Return to Day 1]</a>`, TooHigh, time.Minute},
+		{"too low", `That's not the right answer; your answer is too low.  ` + stuck + `  Because you have guessed incorrectly 4 times on this puzzle, please wait 5 minutes before trying again. <a href="/2023/day/1">[Return to Day 1]</a>`, TooLow, 5 * time.Minute},
+		{"wait", `You gave an answer too recently; you have to wait after submitting an answer before trying again.  You have 1m 23s left to wait. <a href="/2023/day/1">[Return to Day 1]</a>`, Wait, time.Minute + 23*time.Second},
+		{"wait seconds", `You gave an answer too recently; you have to wait after submitting an answer before trying again.  You have 37s left to wait. <a href="/2023/day/1">[Return to Day 1]</a>`, Wait, 37 * time.Second},
+		{"already solved", `You don't seem to be solving the right level.  Did you already complete it? <a href="/2023/day/1">[Return to Day 1]</a>`, AlreadySolved, 0},
+		{"unknown", `Something else entirely.`, Unknown, 0},
+	}
+	for _, c := range cases {
+		resp := ParseResponse(page(c.message))
+		if resp.Outcome != c.outcome || resp.Wait != c.wait {
+			t.Errorf("%v: got %v waiting %v, expected %v waiting %v", c.name, resp.Outcome, resp.Wait, c.outcome, c.wait)
+		}
+		if strings.Contains(resp.Message, "<") || strings.Contains(resp.Message, "Advent of Code") {
+			t.Errorf("%v: message %q has more than the article text", c.name, resp.Message)
+		}
+	}
+}
+
+func TestSubmit(t *testing.T) {
+	requests := &atomic.Int32{}
+	client := testClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Method != http.MethodPost || r.URL.Path != "/2023/day/1/answer" {
+			t.Errorf("Got %v %v, expected a POST for day 1", r.Method, r.URL.Path)
+		}
+		if level := r.PostFormValue("level"); level != "1" {
+			t.Errorf("Got level %q, expected 1", level)
+		}
+		switch answer := r.PostFormValue("answer"); answer {
+		case "42":
+			w.Write([]byte(page("That's the right answer!")))
+		case "100":
+			w.Write([]byte(page("That's not the right answer; your answer is too high.  Please wait one minute before trying again.")))
+		default:
+			t.Errorf("Got answer %q, which should have been refused", answer)
+		}
+	}))
+
+	resp, err := client.Submit(2023, 1, 1, "100")
+	if err != nil || resp.Outcome != TooHigh {
+		t.Fatalf("Got %v (%v), expected too high", resp.Outcome, err)
+	}
+	history, err := LoadHistory(client.historyPath())
+	if err != nil || len(history.Attempts) != 1 || history.Attempts[0].Answer != "100" {
+		t.Fatalf("Got history %+v (%v), expected the attempt", history, err)
+	}
+	if wait := time.Until(history.Attempts[0].NotBefore); wait <= 0 || wait > time.Minute {
+		t.Errorf("Got %v left to wait, expected up to a minute", wait)
+	}
+	if _, err := client.Submit(2023, 1, 1, "42"); err == nil {
+		t.Error("Expected to be told to wait")
+	}
+
+	// Pretend the wait is up
+	history.Attempts[0].NotBefore = time.Now().Add(-time.Second)
+	if err := history.Save(); err != nil {
+		t.Fatal(err)
+	}
+	for _, answer := range []string{"100", "150"} {
+		if _, err := client.Submit(2023, 1, 1, answer); err == nil {
+			t.Errorf("Expected %v to be refused", answer)
+		}
+	}
+	if requests.Load() != 1 {
+		t.Errorf("Made %v requests, expected refused answers not to be sent", requests.Load())
+	}
+
+	resp, err = client.Submit(2023, 1, 1, "42")
+	if err != nil || resp.Outcome != Correct {
+		t.Fatalf("Got %v (%v), expected correct", resp.Outcome, err)
+	}
+	if _, err := client.Submit(2023, 1, 1, "43"); err == nil {
+		t.Error("Expected a solved part to be refused")
+	}
+	if requests.Load() != 2 {
+		t.Errorf("Made %v requests, expected 2", requests.Load())
+	}
+}
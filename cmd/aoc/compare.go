@@ -0,0 +1,78 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/poweredbypie/aoc.2023/aoc"
+)
+
+// Run every implementation of the given days (or all of them) and report where they disagree
+func compare(args []string) error {
+	flags := flag.NewFlagSet("compare", flag.ExitOnError)
+	root := flags.String("root", ".", "root of the repo, where the day directories are")
+	timeout := flags.Duration("timeout", 2*time.Minute, "how long to let each implementation run")
+	flags.Parse(args)
+
+	days := []int{}
+	for _, arg := range flags.Args() {
+		day, err := strconv.Atoi(arg)
+		if err != nil {
+			return fmt.Errorf("Couldn't parse day %q: %w", arg, err)
+		}
+		days = append(days, day)
+	}
+	if len(days) == 0 {
+		all, err := aoc.Days(*root)
+		if err != nil {
+			return err
+		}
+		days = all
+	}
+
+	disagree := 0
+	for _, day := range days {
+		fmt.Printf("Day %v\n", aoc.DayDir(day))
+		results := aoc.Compare(*root, day, *timeout)
+		for _, result := range results {
+			switch {
+			case result.Skipped:
+				fmt.Printf("  %-4v skipped (%v not installed)\n", result.Lang, mustLang(result.Lang).Tool)
+			case result.Err != nil:
+				fmt.Printf("  %-4v failed: %v\n", result.Lang, result.Err)
+			default:
+				fmt.Printf("  %-4v %-16v %v\n", result.Lang, orDash(result.Answers[0]), orDash(result.Answers[1]))
+			}
+		}
+		found := aoc.Disagreements(day, results)
+		for part := 1; part <= 2; part += 1 {
+			lines, ok := found[part]
+			if !ok {
+				continue
+			}
+			disagree += 1
+			fmt.Printf("  Part %v disagrees:\n", part)
+			for _, line := range lines {
+				fmt.Printf("    %v\n", line)
+			}
+		}
+	}
+	if disagree > 0 {
+		return fmt.Errorf("Found %v disagreements", disagree)
+	}
+	return nil
+}
+
+func mustLang(name string) aoc.Lang {
+	lang, _ := aoc.LangFor(name)
+	return lang
+}
+
+func orDash(answer string) string {
+	if answer == "" {
+		return "-"
+	}
+	return answer
+}
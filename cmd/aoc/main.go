@@ -0,0 +1,160 @@
+// Command aoc does the chores around solving puzzles: aoc <command> [flags] [args]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/poweredbypie/aoc.2023/aoc"
+	"github.com/poweredbypie/aoc.2023/logging"
+)
+
+type command struct {
+	usage string
+	run   func(args []string) error
+}
+
+var commands = map[string]command{
+	"compare": {"compare [-root dir] [-timeout duration] [day...]", compare},
+	"fetch":   {"fetch [-o file] [-base-url url] <year> <day>", fetch},
+	"new":     {"new [-root dir] [-fetch] [-year year] [-base-url url] <day>", newDay},
+	"readme":  {"readme [-root dir] [-verify] [-o file]", readme},
+	"submit":  {"submit [-year year] [-answer answer] [-root dir] [-base-url url] <day> <part>", submit},
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: aoc [-v level] <command>")
+	names := []string{}
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  aoc %v\n", commands[name].usage)
+	}
+}
+
+// Parse positional arguments that have to be numbers
+func atois(args []string, names ...string) ([]int, error) {
+	if len(args) != len(names) {
+		return nil, fmt.Errorf("Expected %v arguments (%v), got %v", len(names), names, len(args))
+	}
+	nums := []int{}
+	for idx, arg := range args {
+		num, err := strconv.Atoi(arg)
+		if err != nil {
+			return nil, fmt.Errorf("Couldn't parse %v %q: %w", names[idx], arg, err)
+		}
+		nums = append(nums, num)
+	}
+	return nums, nil
+}
+
+func newClient(baseURL string) (*aoc.Client, error) {
+	session, err := aoc.FindSession()
+	if err != nil {
+		return nil, err
+	}
+	client, err := aoc.NewClient(session)
+	if err != nil {
+		return nil, err
+	}
+	client.BaseURL = baseURL
+	return client, nil
+}
+
+func fetch(args []string) error {
+	flags := flag.NewFlagSet("fetch", flag.ExitOnError)
+	out := flags.String("o", "-", "where to write the input (- for stdout)")
+	baseURL := flags.String("base-url", aoc.DefaultBaseURL, "site to download from")
+	flags.Parse(args)
+	nums, err := atois(flags.Args(), "year", "day")
+	if err != nil {
+		return err
+	}
+	client, err := newClient(*baseURL)
+	if err != nil {
+		return err
+	}
+	input, err := client.Input(nums[0], nums[1])
+	if err != nil {
+		return err
+	}
+	if *out == "-" {
+		_, err = os.Stdout.Write(input)
+		return err
+	}
+	return os.WriteFile(*out, input, 0o644)
+}
+
+func submit(args []string) error {
+	flags := flag.NewFlagSet("submit", flag.ExitOnError)
+	year := flags.Int("year", 2023, "year of the puzzle")
+	answer := flags.String("answer", "", "answer to submit instead of running the solver")
+	root := flags.String("root", ".", "root of the repo, where the day directories are")
+	baseURL := flags.String("base-url", aoc.DefaultBaseURL, "site to submit to")
+	flags.Parse(args)
+	nums, err := atois(flags.Args(), "day", "part")
+	if err != nil {
+		return err
+	}
+	day, part := nums[0], nums[1]
+	if part != 1 && part != 2 {
+		return fmt.Errorf("Part must be 1 or 2, not %v", part)
+	}
+	if *answer == "" {
+		solver, ok := aoc.SolverFor(day)
+		if !ok {
+			return fmt.Errorf("No solver for day %v, pass -answer instead", day)
+		}
+		answers, err := solver.Run(*root)
+		if err != nil {
+			return err
+		}
+		*answer = answers[part-1]
+		if *answer == "" {
+			return fmt.Errorf("Day %v didn't print an answer for part %v", day, part)
+		}
+	}
+	client, err := newClient(*baseURL)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Submitting %v for day %v part %v\n", *answer, day, part)
+	resp, err := client.Submit(*year, day, part, *answer)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Answer is %v\n", resp.Outcome)
+	if resp.Wait > 0 {
+		fmt.Printf("Wait %v before submitting again\n", resp.Wait)
+	}
+	if resp.Outcome == aoc.Unknown {
+		fmt.Println(resp.Message)
+	}
+	return nil
+}
+
+func main() {
+	flag.Usage = usage
+	logging.Flag(flag.CommandLine)
+	flag.Parse()
+	// Days run by the commands log at the same level
+	os.Setenv(logging.LevelEnv, logging.Level.Level().String())
+	if flag.NArg() < 1 {
+		usage()
+		os.Exit(2)
+	}
+	cmd, ok := commands[flag.Arg(0)]
+	if !ok {
+		usage()
+		os.Exit(2)
+	}
+	if err := cmd.run(flag.Args()[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "aoc %v: %v\n", flag.Arg(0), err)
+		os.Exit(1)
+	}
+}
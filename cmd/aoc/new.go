@@ -0,0 +1,223 @@
+package main
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/poweredbypie/aoc.2023/aoc"
+)
+
+// What every new day starts out as
+//
+//go:embed day.tmpl
+var dayTemplate []byte
+
+//go:embed day_test.tmpl
+var dayTestTemplate []byte
+
+// Same as what 01/main.c and 02/main.c start with
+const ignoreC = "// Keeps the Go toolchain from building this alongside main.go\n//go:build ignore\n\n"
+
+// Day an element of a registry is for: the key of a map entry, or the first field of a struct
+func elemDay(elem ast.Expr) (int, bool) {
+	switch elem := elem.(type) {
+	case *ast.KeyValueExpr:
+		return elemDay(elem.Key)
+	case *ast.CompositeLit:
+		if len(elem.Elts) > 0 {
+			return elemDay(elem.Elts[0])
+		}
+	case *ast.BasicLit:
+		if elem.Kind == token.INT {
+			day, err := strconv.Atoi(elem.Value)
+			return day, err == nil
+		}
+	}
+	return 0, false
+}
+
+// Add elem for the day to the composite literal assigned to name in src, keeping the days in order
+func insertByDay(src []byte, name string, day int, elem string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	var lit *ast.CompositeLit
+	ast.Inspect(file, func(node ast.Node) bool {
+		spec, ok := node.(*ast.ValueSpec)
+		if !ok {
+			return lit == nil
+		}
+		for idx, ident := range spec.Names {
+			if ident.Name == name && idx < len(spec.Values) {
+				lit, _ = spec.Values[idx].(*ast.CompositeLit)
+			}
+		}
+		return false
+	})
+	if lit == nil {
+		return nil, fmt.Errorf("Couldn't find %v", name)
+	}
+
+	at := fset.Position(lit.Rbrace).Offset
+	for _, existing := range lit.Elts {
+		if other, ok := elemDay(existing); ok && other > day {
+			at = fset.Position(existing.Pos()).Offset
+			break
+		}
+	}
+	// Go back to the start of the line, and above any comments that go with the element
+	at = bytes.LastIndexByte(src[:at], '\n') + 1
+	for at > 0 {
+		prev := bytes.LastIndexByte(src[:at-1], '\n') + 1
+		if !bytes.HasPrefix(bytes.TrimSpace(src[prev:at]), []byte("//")) {
+			break
+		}
+		at = prev
+	}
+	edited := append([]byte{}, src[:at]...)
+	edited = append(edited, "\t"+elem+",\n"...)
+	edited = append(edited, src[at:]...)
+	return format.Source(edited)
+}
+
+// Files newDay changes, so they can all be put back if something goes wrong partway through
+type changes struct {
+	// Contents from before, or nil for files that didn't exist
+	old   map[string][]byte
+	order []string
+}
+
+func (c *changes) write(path string, contents []byte) error {
+	if _, ok := c.old[path]; !ok {
+		old, err := os.ReadFile(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		c.old[path] = old
+		c.order = append(c.order, path)
+	}
+	return os.WriteFile(path, contents, 0o644)
+}
+
+func (c *changes) undo() {
+	for _, path := range c.order {
+		if c.old[path] == nil {
+			os.Remove(path)
+		} else {
+			os.WriteFile(path, c.old[path], 0o644)
+		}
+	}
+}
+
+// Create a directory for the day with a solver stub, and register it with the runner
+func newDay(args []string) error {
+	flags := flag.NewFlagSet("new", flag.ExitOnError)
+	root := flags.String("root", ".", "root of the repo, where the day directories are")
+	fetchInput := flags.Bool("fetch", false, "download the input for the day too")
+	year := flags.Int("year", 2023, "year to download the input for")
+	baseURL := flags.String("base-url", aoc.DefaultBaseURL, "site to download from")
+	flags.Parse(args)
+	nums, err := atois(flags.Args(), "day")
+	if err != nil {
+		return err
+	}
+	day := nums[0]
+	if day < 1 || day > 25 {
+		return fmt.Errorf("Day must be between 1 and 25, not %v", day)
+	}
+	if _, ok := aoc.SolverFor(day); ok {
+		return fmt.Errorf("Day %v already has a solver", day)
+	}
+	solver := aoc.Solver{Day: day, Parts: [2]string{"Part A answer is", "Part B answer is"}}
+	dir := filepath.Join(*root, solver.Dir())
+	main := filepath.Join(dir, "main.go")
+	if _, err := os.Stat(main); err == nil {
+		return fmt.Errorf("%v already exists", main)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	// Work out everything that changes before touching anything
+	files := map[string][]byte{
+		main:                               dayTemplate,
+		filepath.Join(dir, "main_test.go"): dayTestTemplate,
+	}
+	// Paste the example from the puzzle description in here
+	if example := filepath.Join(dir, "example"); !exists(example) {
+		files[example] = []byte{}
+	}
+	// C files would make the Go toolchain try to build the day with cgo
+	cFiles, _ := filepath.Glob(filepath.Join(dir, "*.[ch]"))
+	for _, path := range cFiles {
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if !bytes.Contains(src, []byte("//go:build")) {
+			files[path] = append([]byte(ignoreC), src...)
+		}
+	}
+	pkg := filepath.Join(*root, "aoc")
+	registry := []struct{ file, name, elem string }{
+		{"solvers.go", "Solvers", fmt.Sprintf("{%v, [2]string{%q, %q}}", day, solver.Parts[0], solver.Parts[1])},
+		{"answers.go", "Answers", strconv.Itoa(day) + `: {"", ""}`},
+	}
+	for _, entry := range registry {
+		path := filepath.Join(pkg, entry.file)
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		edited, err := insertByDay(src, entry.name, day, entry.elem)
+		if err != nil {
+			return fmt.Errorf("%v: %w", path, err)
+		}
+		files[path] = edited
+	}
+
+	madeDir := !exists(dir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	done := &changes{old: map[string][]byte{}}
+	for path, contents := range files {
+		if err := done.write(path, contents); err != nil {
+			done.undo()
+			if madeDir {
+				os.RemoveAll(dir)
+			}
+			return err
+		}
+	}
+	fmt.Printf("Created %v\n", main)
+
+	if *fetchInput {
+		client, err := newClient(*baseURL)
+		if err != nil {
+			return err
+		}
+		input, err := client.Input(*year, day)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(dir, "input"), input, 0o644)
+	}
+	return nil
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/poweredbypie/aoc.2023/aoc"
+)
+
+const (
+	yes = ":white_check_mark:"
+	no  = ":x:"
+	// For when we can't tell if the answers are right
+	unsure = ":grey_question:"
+)
+
+// Whether the Go solver for the day gets the known answers
+func verified(root string, day int) string {
+	solver, ok := aoc.SolverFor(day)
+	if !ok {
+		return ""
+	}
+	known := aoc.Answers[day]
+	if known[0] == "" || known[1] == "" {
+		return unsure
+	}
+	answers, err := solver.Run(root)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return no
+	}
+	if answers != known {
+		fmt.Fprintf(os.Stderr, "Day %v got %v, expected %v\n", day, answers, known)
+		return no
+	}
+	return yes
+}
+
+// Markdown table of which days are solved in which languages
+func langTable(root string, verify bool) (string, error) {
+	days, err := aoc.Days(root)
+	if err != nil {
+		return "", err
+	}
+	header := []string{"Day"}
+	for _, lang := range aoc.Langs {
+		header = append(header, lang.Name)
+	}
+	if verify {
+		header = append(header, "verified")
+	}
+	rows := [][]string{header}
+	for _, day := range days {
+		dir := filepath.Join(root, aoc.DayDir(day))
+		row := []string{aoc.DayDir(day)}
+		for _, lang := range aoc.Langs {
+			if lang.In(dir) {
+				row = append(row, yes)
+			} else {
+				row = append(row, no)
+			}
+		}
+		if verify {
+			row = append(row, verified(root, day))
+		}
+		rows = append(rows, row)
+	}
+
+	widths := make([]int, len(header))
+	for _, row := range rows {
+		for col, cell := range row {
+			widths[col] = max(widths[col], len(cell), 3)
+		}
+	}
+	out := strings.Builder{}
+	writeRow := func(row []string) {
+		for col, cell := range row {
+			fmt.Fprintf(&out, "| %-*v ", widths[col], cell)
+		}
+		out.WriteString("|\n")
+	}
+	writeRow(header)
+	dashes := []string{}
+	for _, width := range widths {
+		dashes = append(dashes, strings.Repeat("-", width))
+	}
+	writeRow(dashes)
+	for _, row := range rows[1:] {
+		writeRow(row)
+	}
+	return out.String(), nil
+}
+
+// Swap the first table in the readme for the new one, or add it to the end if there isn't one
+func replaceTable(readme, table string) string {
+	lines := strings.SplitAfter(readme, "\n")
+	start := -1
+	end := len(lines)
+	for idx, line := range lines {
+		isRow := strings.HasPrefix(line, "|")
+		if start == -1 && isRow {
+			start = idx
+		} else if start != -1 && !isRow {
+			end = idx
+			break
+		}
+	}
+	if start == -1 {
+		if readme != "" && !strings.HasSuffix(readme, "\n") {
+			readme += "\n"
+		}
+		return readme + "\n" + table
+	}
+	return strings.Join(lines[:start], "") + table + strings.Join(lines[end:], "")
+}
+
+func readme(args []string) error {
+	flags := flag.NewFlagSet("readme", flag.ExitOnError)
+	root := flags.String("root", ".", "root of the repo, where the day directories are")
+	verify := flags.Bool("verify", false, "run the Go solvers and add a column for whether they get the known answers")
+	out := flags.String("o", "", "where to write the readme (defaults to README.md under -root, - for stdout)")
+	flags.Parse(args)
+	if flags.NArg() != 0 {
+		return fmt.Errorf("Expected no arguments, got %v", flags.Args())
+	}
+	path := filepath.Join(*root, "README.md")
+	if *out == "" {
+		*out = path
+	}
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	table, err := langTable(*root, *verify)
+	if err != nil {
+		return err
+	}
+	updated := replaceTable(string(existing), table)
+	if *out == "-" {
+		_, err = os.Stdout.WriteString(updated)
+		return err
+	}
+	return os.WriteFile(*out, []byte(updated), 0o644)
+}
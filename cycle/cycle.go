@@ -0,0 +1,90 @@
+// Package cycle finds where a sequence of states (like repeated spin cycles) starts repeating itself.
+package cycle
+
+// After Start steps, the states repeat every Length steps
+type Cycle struct {
+	Start  int
+	Length int
+}
+
+// Step count before Start+Length that ends up in the same state as n steps
+func (c Cycle) Reduce(n int) int {
+	if n < c.Start {
+		return n
+	}
+	return c.Start + (n-c.Start)%c.Length
+}
+
+// State after n steps from init, without actually taking all n steps
+func After[S any](c Cycle, init S, step func(S) S, n int) S {
+	state := init
+	for range c.Reduce(n) {
+		state = step(state)
+	}
+	return state
+}
+
+// Remember a fingerprint of every state until one comes up again.
+// Stops at the first repeat, but needs memory for every state before it.
+func Find[S any, K comparable](init S, step func(S) S, key func(S) K) Cycle {
+	seen := make(map[K]int)
+	state := init
+	for idx := 0; ; idx += 1 {
+		fingerprint := key(state)
+		if first, ok := seen[fingerprint]; ok {
+			return Cycle{first, idx - first}
+		}
+		seen[fingerprint] = idx
+		state = step(state)
+	}
+}
+
+// Floyd's tortoise and hare: constant memory, but takes a few more steps than Find
+func Floyd[S any](init S, step func(S) S, equal func(S, S) bool) Cycle {
+	// Hare goes twice as fast, so they meet somewhere in the cycle
+	tortoise, hare := step(init), step(step(init))
+	for !equal(tortoise, hare) {
+		tortoise, hare = step(tortoise), step(step(hare))
+	}
+	// Meeting point is a multiple of the length away from the start,
+	// so walking both at the same speed from init and the meeting point lines them up at the start
+	c := Cycle{}
+	tortoise = init
+	for !equal(tortoise, hare) {
+		tortoise, hare = step(tortoise), step(hare)
+		c.Start += 1
+	}
+	// Go around once more to get the length
+	c.Length = 1
+	for hare = step(tortoise); !equal(tortoise, hare); hare = step(hare) {
+		c.Length += 1
+	}
+	return c
+}
+
+// Brent's algorithm: constant memory like Floyd, but usually fewer steps
+func Brent[S any](init S, step func(S) S, equal func(S, S) bool) Cycle {
+	// Teleport the tortoise to the hare every power of two steps until they meet
+	power, length := 1, 1
+	tortoise, hare := init, step(init)
+	for !equal(tortoise, hare) {
+		if power == length {
+			tortoise = hare
+			power *= 2
+			length = 0
+		}
+		hare = step(hare)
+		length += 1
+	}
+	// Now that we know the length, start the hare that far ahead and walk both until they meet at the start
+	c := Cycle{Length: length}
+	tortoise, hare = init, init
+	for range length {
+		hare = step(hare)
+	}
+	for !equal(tortoise, hare) {
+		tortoise, hare = step(tortoise), step(hare)
+		c.Start += 1
+	}
+	return c
+}
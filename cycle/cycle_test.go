@@ -0,0 +1,68 @@
+package cycle
+
+import (
+	"fmt"
+	"testing"
+)
+
+// 0, 1, ... up to start+length-1, then back to start
+func sequence(c Cycle) func(int) int {
+	return func(state int) int {
+		if state+1 < c.Start+c.Length {
+			return state + 1
+		}
+		return c.Start
+	}
+}
+
+func equal(one, two int) bool {
+	return one == two
+}
+
+func key(state int) int {
+	return state
+}
+
+var cycles = []Cycle{
+	{0, 1},
+	{0, 5},
+	{1, 1},
+	{1, 2},
+	{3, 4},
+	{10, 7},
+	{5, 13},
+	{0, 64},
+	{63, 1},
+}
+
+func TestDetect(t *testing.T) {
+	for _, want := range cycles {
+		step := sequence(want)
+		found := map[string]Cycle{
+			"Find":  Find(0, step, key),
+			"Floyd": Floyd(0, step, equal),
+			"Brent": Brent(0, step, equal),
+		}
+		for name, got := range found {
+			if got != want {
+				t.Errorf("%v found %+v, expected %+v", name, got, want)
+			}
+		}
+	}
+}
+
+func TestAfter(t *testing.T) {
+	for _, c := range cycles {
+		t.Run(fmt.Sprintf("%v+%v", c.Start, c.Length), func(t *testing.T) {
+			step := sequence(c)
+			// Step one at a time and compare, covering before, at and well past the start
+			state := 0
+			for n := 0; n <= c.Start+3*c.Length; n += 1 {
+				if got := After(c, 0, step, n); got != state {
+					t.Errorf("After %v steps got %v, expected %v", n, got, state)
+				}
+				state = step(state)
+			}
+		})
+	}
+}
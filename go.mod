@@ -0,0 +1,3 @@
+module github.com/poweredbypie/aoc.2023
+
+go 1.23
@@ -0,0 +1,290 @@
+// Package grid has the 2D byte grids and coordinates most of the puzzles are built on.
+package grid
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"slices"
+
+	"github.com/poweredbypie/aoc.2023/parse"
+)
+
+type Coord struct {
+	Row, Col int
+}
+
+func (c Coord) Format(f fmt.State, _ rune) {
+	f.Write([]byte(fmt.Sprintf("(%v, %v)", c.Row, c.Col)))
+}
+
+func (c Coord) Equal(coord Coord) bool {
+	return c.Row == coord.Row && c.Col == coord.Col
+}
+
+// One step over in dir. This can step off the grid, so check InBounds() before using the result with At()
+func (c Coord) Move(dir Dir) Coord {
+	switch dir {
+	case Up:
+		return Coord{c.Row - 1, c.Col}
+	case Left:
+		return Coord{c.Row, c.Col - 1}
+	case Right:
+		return Coord{c.Row, c.Col + 1}
+	case Down:
+		return Coord{c.Row + 1, c.Col}
+	default:
+		panic("Unexpected direction")
+	}
+}
+
+type Dir int
+
+// In this order so sorting directions always gives the same result
+const (
+	Up Dir = iota
+	Left
+	Right
+	Down
+)
+
+var Dirs = []Dir{Up, Left, Right, Down}
+
+func (d Dir) Format(f fmt.State, verb rune) {
+	text := "Unknown"
+	switch d {
+	case Up:
+		text = "Up"
+	case Left:
+		text = "Left"
+	case Right:
+		text = "Right"
+	case Down:
+		text = "Down"
+	}
+	f.Write([]byte(text))
+}
+
+func (d Dir) Flip() Dir {
+	switch d {
+	case Up:
+		return Down
+	case Left:
+		return Right
+	case Right:
+		return Left
+	case Down:
+		return Up
+	default:
+		panic("Unexpected direction")
+	}
+}
+
+type Grid struct {
+	Lines      [][]byte
+	Rows, Cols int
+}
+
+// Every line has to be the same length
+func New(lines [][]byte) *Grid {
+	g := &Grid{Lines: lines, Rows: len(lines)}
+	if g.Rows > 0 {
+		g.Cols = len(lines[0])
+	}
+	for idx, line := range lines {
+		if len(line) != g.Cols {
+			panic(fmt.Sprintf("Line %v has differing length from first line (%v vs. %v)", idx, g.Cols, len(line)))
+		}
+	}
+	return g
+}
+
+func Filled(rows, cols int, char byte) *Grid {
+	lines := [][]byte{}
+	for row := 0; row < rows; row += 1 {
+		line := make([]byte, cols)
+		for col := range line {
+			line[col] = char
+		}
+		lines = append(lines, line)
+	}
+	return New(lines)
+}
+
+func FromStrings(strs []string) *Grid {
+	lines := [][]byte{}
+	for _, str := range strs {
+		lines = append(lines, []byte(str))
+	}
+	return New(lines)
+}
+
+// Read lines until the end, or until the first blank line if there's more after it
+func Read(scan *bufio.Scanner) *Grid {
+	lines := parse.Block(scan)
+	// Blank lines before the grid don't count as an empty grid
+	for lines != nil && len(lines) == 0 {
+		lines = parse.Block(scan)
+	}
+	return FromStrings(lines)
+}
+
+func ReadFile(file *os.File) *Grid {
+	return Read(bufio.NewScanner(file))
+}
+
+func (g *Grid) InBounds(coord Coord) bool {
+	return coord.Row >= 0 && coord.Row < g.Rows && coord.Col >= 0 && coord.Col < g.Cols
+}
+
+// Panics if coord isn't in bounds, use Get() if that can happen
+func (g *Grid) At(coord Coord) *byte {
+	return &g.Lines[coord.Row][coord.Col]
+}
+
+func (g *Grid) Get(coord Coord) (byte, bool) {
+	if !g.InBounds(coord) {
+		return 0, false
+	}
+	return *g.At(coord), true
+}
+
+// Every coord, row by row
+func (g *Grid) All() iter.Seq[Coord] {
+	return func(yield func(Coord) bool) {
+		c := Coord{}
+		for c.Row = 0; c.Row < g.Rows; c.Row += 1 {
+			for c.Col = 0; c.Col < g.Cols; c.Col += 1 {
+				if !yield(c) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Neighbors above, left, right and below that are in bounds:
+// .*.
+// *c*
+// .*.
+func (g *Grid) Neighbors4(coord Coord) iter.Seq[Coord] {
+	return func(yield func(Coord) bool) {
+		for _, dir := range Dirs {
+			next := coord.Move(dir)
+			if g.InBounds(next) && !yield(next) {
+				return
+			}
+		}
+	}
+}
+
+// Same as Neighbors4, but with the diagonals too:
+// ***
+// *c*
+// ***
+func (g *Grid) Neighbors8(coord Coord) iter.Seq[Coord] {
+	return func(yield func(Coord) bool) {
+		for row := coord.Row - 1; row <= coord.Row+1; row += 1 {
+			for col := coord.Col - 1; col <= coord.Col+1; col += 1 {
+				next := Coord{row, col}
+				if next.Equal(coord) || !g.InBounds(next) {
+					continue
+				}
+				if !yield(next) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Shares memory with the grid, so writes go through
+func (g *Grid) Row(row int) []byte {
+	return g.Lines[row]
+}
+
+// Columns aren't contiguous, so this is a copy
+func (g *Grid) Col(col int) []byte {
+	line := make([]byte, g.Rows)
+	for row := range line {
+		line[row] = g.Lines[row][col]
+	}
+	return line
+}
+
+// Flip over the main diagonal, so rows become columns
+func (g *Grid) Transpose() *Grid {
+	lines := [][]byte{}
+	for col := 0; col < g.Cols; col += 1 {
+		lines = append(lines, g.Col(col))
+	}
+	return New(lines)
+}
+
+// Turn a quarter clockwise
+func (g *Grid) RotateRight() *Grid {
+	rotated := g.Transpose()
+	for _, line := range rotated.Lines {
+		slices.Reverse(line)
+	}
+	return rotated
+}
+
+// Turn a quarter counterclockwise
+func (g *Grid) RotateLeft() *Grid {
+	rotated := g.Transpose()
+	slices.Reverse(rotated.Lines)
+	return rotated
+}
+
+func (g *Grid) Clone() *Grid {
+	lines := [][]byte{}
+	for _, line := range g.Lines {
+		lines = append(lines, slices.Clone(line))
+	}
+	return New(lines)
+}
+
+func (g *Grid) Equal(other *Grid) bool {
+	if g.Rows != other.Rows || g.Cols != other.Cols {
+		return false
+	}
+	for row := range g.Lines {
+		if !slices.Equal(g.Lines[row], other.Lines[row]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (g *Grid) Count(char byte) int {
+	count := 0
+	for c := range g.All() {
+		if *g.At(c) == char {
+			count += 1
+		}
+	}
+	return count
+}
+
+func (g *Grid) WriteTo(w io.Writer) (int64, error) {
+	out := bufio.NewWriter(w)
+	for _, line := range g.Lines {
+		out.Write(line)
+		out.WriteByte('\n')
+	}
+	return int64(g.Rows * (g.Cols + 1)), out.Flush()
+}
+
+// For visualization
+func (g *Grid) Dump(filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = g.WriteTo(file)
+	return err
+}
@@ -0,0 +1,30 @@
+// Package logging sets up log/slog the same way for every day and the aoc command
+package logging
+
+import (
+	"flag"
+	"log/slog"
+	"os"
+)
+
+// Environment variable the aoc command uses to pass its -v level down to the days it runs
+const LevelEnv = "AOC_LOG"
+
+// Records below this are dropped. Defaults to info, so debug records only show up when asked for
+var Level = new(slog.LevelVar)
+
+func init() {
+	if env := os.Getenv(LevelEnv); env != "" {
+		// A bad level just leaves the default, it's not worth crashing a solver over
+		Level.UnmarshalText([]byte(env))
+	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: Level})))
+}
+
+// Add -v to the flag set so the level can be set like "-v debug" or "-v warn"
+func Flag(flags *flag.FlagSet) {
+	// TextVar wants the default as the same type as the variable
+	def := new(slog.LevelVar)
+	def.Set(Level.Level())
+	flags.TextVar(Level, "v", def, "only log records at or above this level (debug, info, warn or error)")
+}
@@ -0,0 +1,43 @@
+// Package parallel runs independent pieces of work (usually one line of input each) across goroutines.
+package parallel
+
+import (
+	"runtime"
+	"sync"
+)
+
+type Number interface {
+	~int | ~int64 | ~uint64 | ~float64
+}
+
+// Call f on every item using at most GOMAXPROCS workers.
+// Results come back in the same order as items, no matter which worker finished first.
+func Map[T, R any](items []T, f func(T) R) []R {
+	results := make([]R, len(items))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for range min(runtime.GOMAXPROCS(0), len(items)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range next {
+				results[idx] = f(items[idx])
+			}
+		}()
+	}
+	for idx := range items {
+		next <- idx
+	}
+	close(next)
+	wg.Wait()
+	return results
+}
+
+// Same as Map, but add up the results (in order, so floats always round the same way)
+func Sum[T any, N Number](items []T, f func(T) N) N {
+	var sum N
+	for _, result := range Map(items, f) {
+		sum += result
+	}
+	return sum
+}
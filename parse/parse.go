@@ -0,0 +1,94 @@
+// Package parse has the little helpers almost every puzzle input needs:
+// pulling integers out of a line, splitting on blank lines and reading "Label: values" lines.
+package parse
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+func isDigit(char byte) bool {
+	return char >= '0' && char <= '9'
+}
+
+// Same as Ints, but appends to dst so a buffer can be reused between lines
+func AppendInts(dst []int, str string) []int {
+	for idx := 0; idx < len(str); {
+		// A '-' only counts as a sign if a digit comes right after it
+		neg := str[idx] == '-' && idx+1 < len(str) && isDigit(str[idx+1])
+		if !neg && !isDigit(str[idx]) {
+			idx += 1
+			continue
+		}
+		if neg {
+			idx += 1
+		}
+		num := 0
+		for ; idx < len(str) && isDigit(str[idx]); idx += 1 {
+			num = num*10 + int(str[idx]-'0')
+		}
+		if neg {
+			num = -num
+		}
+		dst = append(dst, num)
+	}
+	return dst
+}
+
+// Every integer in str, in order. Anything that isn't a digit separates them,
+// except for a '-' right before a digit, which makes it negative (so "1-2" is 1 and -2).
+func Ints(str string) []int {
+	return AppendInts(nil, str)
+}
+
+// Integers separated by sep, like "1,2,3" with ",". Empty fields (from repeated separators) are skipped,
+// but anything else that isn't a number is an error.
+func IntsSep(str, sep string) ([]int, error) {
+	nums := []int{}
+	for _, field := range strings.Split(str, sep) {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		num, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, fmt.Errorf("Couldn't convert %q to a number: %w", field, err)
+		}
+		nums = append(nums, num)
+	}
+	return nums, nil
+}
+
+// Split "Label: values" at the first ':'. Both sides have surrounding spaces trimmed.
+func Labeled(line string) (label, rest string, ok bool) {
+	label, rest, ok = strings.Cut(line, ":")
+	return strings.TrimSpace(label), strings.TrimSpace(rest), ok
+}
+
+// Same as Labeled, but with the integers after the ':' (like "seeds: 79 14 55 13")
+func LabeledInts(line string) (string, []int, bool) {
+	label, rest, ok := Labeled(line)
+	if !ok {
+		return "", nil, false
+	}
+	return label, Ints(rest), true
+}
+
+// Lines up to the next blank line (or the end). A blank line straight away gives an empty block,
+// like a section with nothing in it. Returns nil once there's nothing left.
+func Block(scan *bufio.Scanner) []string {
+	var lines []string
+	for scan.Scan() {
+		line := scan.Text()
+		if line == "" {
+			if lines == nil {
+				return []string{}
+			}
+			break
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,89 @@
+package parse
+
+import (
+	"bufio"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestInts(t *testing.T) {
+	cases := []struct {
+		str  string
+		want []int
+	}{
+		{"", []int{}},
+		{"seeds: 79 14 55 13", []int{79, 14, 55, 13}},
+		{"-5 3 -12", []int{-5, 3, -12}},
+		// A '-' right before a digit is a sign, even with a number in front of it
+		{"1-2", []int{1, -2}},
+		// But not when something else is in the way
+		{"a - 3, x-y", []int{3}},
+		{"--4", []int{-4}},
+		{"Game 12: 3 blue", []int{12, 3}},
+	}
+	for _, c := range cases {
+		if got := Ints(c.str); !slices.Equal(got, c.want) {
+			t.Errorf("Ints(%q) got %v, expected %v", c.str, got, c.want)
+		}
+	}
+	// Appending reuses what's there
+	if got := AppendInts([]int{7}, "8 9"); !slices.Equal(got, []int{7, 8, 9}) {
+		t.Errorf("AppendInts got %v, expected [7 8 9]", got)
+	}
+}
+
+func TestIntsSep(t *testing.T) {
+	cases := []struct {
+		str, sep string
+		want     []int
+	}{
+		{"1,1,3", ",", []int{1, 1, 3}},
+		{"1, -2 ,3", ",", []int{1, -2, 3}},
+		{"4,,5,", ",", []int{4, 5}},
+		{"6 | 7", "|", []int{6, 7}},
+		{"", ",", []int{}},
+	}
+	for _, c := range cases {
+		got, err := IntsSep(c.str, c.sep)
+		if err != nil || !slices.Equal(got, c.want) {
+			t.Errorf("IntsSep(%q, %q) got %v (%v), expected %v", c.str, c.sep, got, err, c.want)
+		}
+	}
+	if _, err := IntsSep("1,two,3", ","); err == nil {
+		t.Error("Expected an error for a field that isn't a number")
+	}
+}
+
+func TestLabeled(t *testing.T) {
+	label, rest, ok := Labeled("  Time:   7  15   30 ")
+	if label != "Time" || rest != "7  15   30" || !ok {
+		t.Errorf("Got %q, %q, %v", label, rest, ok)
+	}
+	if _, _, ok := Labeled("no label here"); ok {
+		t.Error("Expected no label without a ':'")
+	}
+	label, nums, ok := LabeledInts("seeds: 79 14")
+	if label != "seeds" || !slices.Equal(nums, []int{79, 14}) || !ok {
+		t.Errorf("Got %q, %v, %v", label, nums, ok)
+	}
+}
+
+func TestBlock(t *testing.T) {
+	// Day 05 style: a header straight after a blank line is the next section, not part of this one
+	scan := bufio.NewScanner(strings.NewReader("seed-to-soil map:\n\nsoil-to-fertilizer map:\n0 15 37\n37 52 2\n\nlast\n"))
+	scan.Scan()
+	if block := Block(scan); block == nil || len(block) != 0 {
+		t.Errorf("Got %q for an empty section, expected an empty block", block)
+	}
+	scan.Scan()
+	if block := Block(scan); !slices.Equal(block, []string{"0 15 37", "37 52 2"}) {
+		t.Errorf("Got %q, expected both remaps", block)
+	}
+	if block := Block(scan); !slices.Equal(block, []string{"last"}) {
+		t.Errorf("Got %q, expected the last block", block)
+	}
+	if block := Block(scan); block != nil {
+		t.Errorf("Got %q once everything was read, expected nil", block)
+	}
+}